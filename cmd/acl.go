@@ -0,0 +1,282 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/danluki/taskvault/taskvault"
+	"github.com/spf13/cobra"
+)
+
+var (
+	aclDescription string
+	aclPolicies    []string
+	aclManagement  bool
+	aclRules       []string
+	aclOperator    string
+	aclAgent       string
+)
+
+var aclCmd = &cobra.Command{
+	Use:   "acl",
+	Short: "Manage ACL tokens and policies",
+}
+
+var aclBootstrapCmd = &cobra.Command{
+	Use:   "bootstrap",
+	Short: "Create the initial management token",
+	Long: `Create the initial management token. This only works once, use the
+token to create every other token.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var token taskvault.ACLToken
+		if err := aclCall(http.MethodPost, "/v1/acl/bootstrap", nil, &token); err != nil {
+			return err
+		}
+		printACLToken(&token)
+		return nil
+	},
+}
+
+var aclTokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Manage ACL tokens",
+}
+
+var aclTokenCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a token",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var token taskvault.ACLToken
+		err := aclCall(http.MethodPost, "/v1/acl/token", &taskvault.ACLToken{
+			Description: aclDescription,
+			Policies:    aclPolicies,
+			Management:  aclManagement,
+		}, &token)
+		if err != nil {
+			return err
+		}
+		printACLToken(&token)
+		return nil
+	},
+}
+
+var aclTokenListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the tokens, without their secrets",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var tokens []taskvault.ACLToken
+		if err := aclCall(http.MethodGet, "/v1/acl/tokens", nil, &tokens); err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "AccessorID\tDescription\tPolicies\tManagement")
+		for _, t := range tokens {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%t\n",
+				t.AccessorID, t.Description, strings.Join(t.Policies, ","), t.Management)
+		}
+		return w.Flush()
+	},
+}
+
+var aclTokenReadCmd = &cobra.Command{
+	Use:   "read <accessor>",
+	Short: "Show a token along with its secret",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var token taskvault.ACLToken
+		if err := aclCall(http.MethodGet, "/v1/acl/token/"+url.PathEscape(args[0]), nil, &token); err != nil {
+			return err
+		}
+		printACLToken(&token)
+		return nil
+	},
+}
+
+var aclTokenDeleteCmd = &cobra.Command{
+	Use:   "delete <accessor>",
+	Short: "Delete a token",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := aclCall(http.MethodDelete, "/v1/acl/token/"+url.PathEscape(args[0]), nil, nil); err != nil {
+			return err
+		}
+		fmt.Printf("Deleted token %q\n", args[0])
+		return nil
+	},
+}
+
+var aclPolicyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Manage ACL policies",
+}
+
+var aclPolicyCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create or replace a policy",
+	Long: `Create or replace a policy. Each --rule grants access to the keys
+starting with a prefix, as prefix=read|write|deny. The rule with the longest
+matching prefix applies.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		policy := &taskvault.ACLPolicy{
+			Description: aclDescription,
+			Operator:    aclOperator,
+			Agent:       aclAgent,
+		}
+		for _, rule := range aclRules {
+			prefix, access, ok := strings.Cut(rule, "=")
+			if !ok {
+				return fmt.Errorf("acl: invalid rule %q, expected prefix=access", rule)
+			}
+			policy.Rules = append(policy.Rules, taskvault.ACLRule{Prefix: prefix, Access: access})
+		}
+
+		var stored taskvault.ACLPolicy
+		if err := aclCall(http.MethodPut, "/v1/acl/policy/"+url.PathEscape(args[0]), policy, &stored); err != nil {
+			return err
+		}
+		printACLPolicy(&stored)
+		return nil
+	},
+}
+
+var aclPolicyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the policies",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var policies []taskvault.ACLPolicy
+		if err := aclCall(http.MethodGet, "/v1/acl/policies", nil, &policies); err != nil {
+			return err
+		}
+
+		for i := range policies {
+			if i > 0 {
+				fmt.Println()
+			}
+			printACLPolicy(&policies[i])
+		}
+		return nil
+	},
+}
+
+var aclPolicyDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a policy",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := aclCall(http.MethodDelete, "/v1/acl/policy/"+url.PathEscape(args[0]), nil, nil); err != nil {
+			return err
+		}
+		fmt.Printf("Deleted policy %q\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	taskvaultCmd.AddCommand(aclCmd)
+	aclCmd.AddCommand(aclBootstrapCmd, aclTokenCmd, aclPolicyCmd)
+	aclTokenCmd.AddCommand(aclTokenCreateCmd, aclTokenListCmd, aclTokenReadCmd, aclTokenDeleteCmd)
+	aclPolicyCmd.AddCommand(aclPolicyCreateCmd, aclPolicyListCmd, aclPolicyDeleteCmd)
+
+	addHTTPFlags(aclCmd.PersistentFlags())
+
+	aclTokenCreateCmd.Flags().StringVar(
+		&aclDescription, "description", "",
+		"Description of the token",
+	)
+	aclTokenCreateCmd.Flags().StringSliceVar(
+		&aclPolicies, "policy", nil,
+		"Name of a policy to grant, may be repeated",
+	)
+	aclTokenCreateCmd.Flags().BoolVar(
+		&aclManagement, "management", false,
+		"Grant everything, including ACL management",
+	)
+	aclPolicyCreateCmd.Flags().StringVar(
+		&aclDescription, "description", "",
+		"Description of the policy",
+	)
+	aclPolicyCreateCmd.Flags().StringArrayVar(
+		&aclRules, "rule", nil,
+		"Key rule as prefix=read|write|deny, may be repeated",
+	)
+	aclPolicyCreateCmd.Flags().StringVar(
+		&aclOperator, "operator", "",
+		"Access to the operator endpoints (read|write|deny)",
+	)
+	aclPolicyCreateCmd.Flags().StringVar(
+		&aclAgent, "agent", "",
+		"Access to the agent endpoints (read|write|deny)",
+	)
+}
+
+// aclCall sends in as JSON to the ACL endpoint at path and decodes the
+// response into out.
+func aclCall(method string, path string, in any, out any) error {
+	client, base, err := apiClient()
+	if err != nil {
+		return err
+	}
+
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+
+	req, err := newAPIRequest(method, base+path, body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return apiError("acl", resp)
+	}
+	if out == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func printACLToken(t *taskvault.ACLToken) {
+	fmt.Printf("AccessorID: %s\n", t.AccessorID)
+	fmt.Printf("SecretID: %s\n", t.SecretID)
+	fmt.Printf("Description: %s\n", t.Description)
+	fmt.Printf("Policies: %s\n", strings.Join(t.Policies, ", "))
+	fmt.Printf("Management: %t\n", t.Management)
+}
+
+func printACLPolicy(p *taskvault.ACLPolicy) {
+	fmt.Printf("Name: %s\n", p.Name)
+	fmt.Printf("Description: %s\n", p.Description)
+	fmt.Printf("Operator: %s\n", p.Operator)
+	fmt.Printf("Agent: %s\n", p.Agent)
+	fmt.Println("Rules:")
+	for _, r := range p.Rules {
+		fmt.Printf("  %q = %s\n", r.Prefix, r.Access)
+	}
+}
@@ -60,40 +60,47 @@ func handleSignals() int {
 	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
 
 	var sig os.Signal
+WAIT:
 	select {
 	case s := <-signalCh:
 		sig = s
 	case err := <-agent.RetryJoinCh():
 		fmt.Println("[ERR] agent: Retry join failed: ", err)
 		return 1
+	case <-agent.ShutdownCh():
+		// Left through the API.
+		return 0
+	}
+	fmt.Printf("Caught signal: %v\n", sig)
+
+	if sig == syscall.SIGHUP {
+		if err := agent.Reload(); err != nil {
+			log.WithError(err).Error("agent: Failed to reload")
+		}
+		goto WAIT
 	}
-	fmt.Printf("Caught signal: %v", sig)
 
 	if sig != syscall.SIGTERM && sig != os.Interrupt {
 		return 1
 	}
 
 	log.Info("agent: Gracefully shutting down agent...")
+	gracefulCh := make(chan error, 1)
 	go func() {
-		if err := agent.Stop(); err != nil {
-			fmt.Printf("Error: %s", err)
-			log.Error(fmt.Sprintf("Error: %s", err))
-			return
-		}
+		gracefulCh <- agent.Stop()
 	}()
 
-	gracefulCh := make(chan struct{})
-
-	time.Sleep(1 * time.Second)
-
-	close(gracefulCh)
-
 	select {
 	case <-signalCh:
 		return 1
 	case <-time.After(gracefulTimeout):
 		return 1
-	case <-gracefulCh:
+	case err := <-gracefulCh:
+		if err != nil {
+			fmt.Printf("Error: %s", err)
+			log.Error(fmt.Sprintf("Error: %s", err))
+			return 1
+		}
 		return 0
 	}
 }
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+
+	"github.com/danluki/taskvault/taskvault"
+	"github.com/spf13/pflag"
+)
+
+var (
+	httpAddr string
+	aclToken string
+)
+
+// addHTTPFlags adds the flags of commands that call the HTTP API.
+func addHTTPFlags(flags *pflag.FlagSet) {
+	flags.StringVar(
+		&httpAddr, "http-addr", "127.0.0.1:8080",
+		"HTTP address of the agent",
+	)
+	flags.StringVar(
+		&config.CAFile, "ca-file", "",
+		"PEM encoded CA certificates used to verify the agent, enables HTTPS",
+	)
+	flags.StringVar(
+		&config.CertFile, "cert-file", "",
+		"PEM encoded client certificate, for agents that verify clients",
+	)
+	flags.StringVar(
+		&config.KeyFile, "key-file", "",
+		"PEM encoded private key of cert-file",
+	)
+	flags.StringVar(
+		&config.TLSServerName, "tls-server-name", "",
+		"Name the agent certificate is verified against, the host of http-addr by default",
+	)
+	addTokenFlag(flags)
+}
+
+func addTokenFlag(flags *pflag.FlagSet) {
+	flags.StringVar(
+		&aclToken, "token", os.Getenv("TASKVAULT_TOKEN"),
+		"Secret of the ACL token to send, TASKVAULT_TOKEN by default",
+	)
+}
+
+// apiClient returns the client and base URL of the HTTP API, HTTPS is used
+// when a CA file is given.
+func apiClient() (*http.Client, string, error) {
+	if config.CAFile == "" {
+		return http.DefaultClient, fmt.Sprintf("http://%s", httpAddr), nil
+	}
+
+	host, _, err := net.SplitHostPort(httpAddr)
+	if err != nil {
+		return nil, "", err
+	}
+	tlsConfig, err := taskvault.ClientTLSConfig(config, host)
+	if err != nil {
+		return nil, "", err
+	}
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+	return client, fmt.Sprintf("https://%s", httpAddr), nil
+}
+
+// newAPIRequest returns a request to the HTTP API carrying the ACL token.
+func newAPIRequest(method string, url string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	if aclToken != "" {
+		req.Header.Set("X-Taskvault-Token", aclToken)
+	}
+
+	return req, nil
+}
+
+func apiError(prefix string, resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	return fmt.Errorf("%s: unexpected status %s: %s", prefix, resp.Status, body)
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/danluki/taskvault/taskvault"
+	"github.com/spf13/cobra"
+)
+
+var peerID string
+
+var raftCmd = &cobra.Command{
+	Use:   "raft",
+	Short: "Inspect and change the Raft configuration of the cluster",
+}
+
+var raftListPeersCmd = &cobra.Command{
+	Use:   "list-peers",
+	Short: "List the servers of the Raft configuration",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return raftListPeers()
+	},
+}
+
+var raftRemovePeerCmd = &cobra.Command{
+	Use:   "remove-peer --id=<server-id>",
+	Short: "Remove a server from the Raft configuration",
+	Long: `Remove the server given by --id from the Raft configuration. This is
+meant for servers that failed and will not come back, servers that leave the
+cluster remove themselves. The last voter can not be removed.
+
+Flags take two dashes, -id=n3 is read as the shorthand flags -i -d.`,
+	Example: "  taskvault raft remove-peer --id=n3",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return raftRemovePeer()
+	},
+}
+
+var raftTransferLeaderCmd = &cobra.Command{
+	Use:   "transfer-leader [--id=<server-id>]",
+	Short: "Transfer leadership to another server",
+	Long: `Transfer leadership to the server given by --id, or to the most up to
+date voter when it is omitted. Use it to move leadership off a server before
+taking it down for maintenance.`,
+	Example: "  taskvault raft transfer-leader --id=n2",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return raftTransferLeader()
+	},
+}
+
+func init() {
+	taskvaultCmd.AddCommand(raftCmd)
+	raftCmd.AddCommand(raftListPeersCmd, raftRemovePeerCmd, raftTransferLeaderCmd)
+
+	raftCmd.PersistentFlags().StringVar(
+		&rpcAddr, "rpc-addr", "127.0.0.1:6868",
+		"RPC address of any server of the cluster",
+	)
+	raftCmd.PersistentFlags().StringVar(
+		&config.CAFile, "ca-file", "",
+		"PEM encoded CA certificates used to verify the server",
+	)
+	raftCmd.PersistentFlags().StringVar(
+		&config.CertFile, "cert-file", "",
+		"PEM encoded client certificate, required when the cluster uses TLS",
+	)
+	raftCmd.PersistentFlags().StringVar(
+		&config.KeyFile, "key-file", "",
+		"PEM encoded private key of cert-file",
+	)
+	raftCmd.PersistentFlags().StringVar(
+		&config.TLSServerName, "tls-server-name", "",
+		"Name the server certificate is verified against, the host of rpc-addr by default",
+	)
+	addTokenFlag(raftCmd.PersistentFlags())
+	raftRemovePeerCmd.Flags().StringVar(
+		&peerID, "id", "",
+		"Raft ID of the server to remove",
+	)
+	_ = raftRemovePeerCmd.MarkFlagRequired("id")
+	raftTransferLeaderCmd.Flags().StringVar(
+		&peerID, "id", "",
+		"Raft ID of the server to transfer leadership to",
+	)
+}
+
+func raftListPeers() error {
+	client, err := raftClient()
+	if err != nil {
+		return err
+	}
+	defer client.Shutdown()
+
+	reply, err := client.RaftGetConfiguration(rpcAddr)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "Node\tID\tAddress\tState\tVoter\tRaftProtocol")
+	for _, s := range reply.Servers {
+		state := "follower"
+		if s.Leader {
+			state = "leader"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%t\t%s\n",
+			s.Node, s.Id, s.Address, state, s.Voter, s.RaftProtocol)
+	}
+
+	return w.Flush()
+}
+
+func raftRemovePeer() error {
+	client, err := raftClient()
+	if err != nil {
+		return err
+	}
+	defer client.Shutdown()
+
+	if err := client.RaftRemovePeerByID(rpcAddr, peerID); err != nil {
+		return err
+	}
+
+	fmt.Printf("Removed peer with id %q\n", peerID)
+	return nil
+}
+
+func raftTransferLeader() error {
+	client, err := raftClient()
+	if err != nil {
+		return err
+	}
+	defer client.Shutdown()
+
+	reply, err := client.RaftTransferLeader(rpcAddr, peerID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Transferred leadership to %q (%s)\n", reply.Id, reply.Address)
+	return nil
+}
+
+func raftClient() (taskvault.TaskvaultGRPCClient, error) {
+	dialOpt, err := taskvault.RPCDialOption(config)
+	if err != nil {
+		return nil, err
+	}
+
+	client := taskvault.NewGRPCClient(dialOpt, nil, taskvault.InitLogger("error", ""))
+	return client.WithToken(aclToken), nil
+}
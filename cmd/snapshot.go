@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/danluki/taskvault/taskvault"
+	"github.com/spf13/cobra"
+)
+
+var snapshotStale bool
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Save, restore and inspect snapshots of the cluster state",
+}
+
+var snapshotSaveCmd = &cobra.Command{
+	Use:   "save <file>",
+	Short: "Save a snapshot of the cluster state to a file",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return snapshotSave(args[0])
+	},
+}
+
+var snapshotRestoreCmd = &cobra.Command{
+	Use:   "restore <file>",
+	Short: "Restore the cluster state from a snapshot file",
+	Long: `Restore the cluster state from a snapshot file. This replaces the
+state of every server and should only be used for disaster recovery.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return snapshotRestore(args[0])
+	},
+}
+
+var snapshotInspectCmd = &cobra.Command{
+	Use:   "inspect <file>",
+	Short: "Verify a snapshot file and show its metadata",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return snapshotInspect(args[0])
+	},
+}
+
+func init() {
+	taskvaultCmd.AddCommand(snapshotCmd)
+	snapshotCmd.AddCommand(snapshotSaveCmd, snapshotRestoreCmd, snapshotInspectCmd)
+
+	addHTTPFlags(snapshotCmd.PersistentFlags())
+	snapshotSaveCmd.Flags().BoolVar(
+		&snapshotStale, "stale", false,
+		"Take the snapshot on the agent even if it is not the leader",
+	)
+}
+
+func snapshotSave(path string) error {
+	client, url, err := snapshotClient()
+	if err != nil {
+		return err
+	}
+	if snapshotStale {
+		url += "?stale"
+	}
+
+	req, err := newAPIRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return apiError("snapshot", resp)
+	}
+
+	// Write to a temporary file first so a failed download never replaces
+	// an existing snapshot.
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, resp.Body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	meta, err := inspectSnapshotFile(tmp.Name())
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+
+	fmt.Printf("Saved snapshot at index %d to %s\n", meta.Index, path)
+	return nil
+}
+
+func snapshotRestore(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	client, url, err := snapshotClient()
+	if err != nil {
+		return err
+	}
+
+	req, err := newAPIRequest(http.MethodPut, url, f)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/gzip")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return apiError("snapshot", resp)
+	}
+
+	fmt.Printf("Restored snapshot %s\n", path)
+	return nil
+}
+
+func snapshotInspect(path string) error {
+	meta, err := inspectSnapshotFile(path)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("ID: %s\n", meta.ID)
+	fmt.Printf("Index: %d\n", meta.Index)
+	fmt.Printf("Term: %d\n", meta.Term)
+	fmt.Printf("Version: %s\n", meta.Version)
+	fmt.Printf("Size: %d\n", meta.Size)
+	return nil
+}
+
+func inspectSnapshotFile(path string) (*taskvault.SnapshotMeta, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	meta, state, err := taskvault.ReadSnapshot(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return meta, state.Close()
+}
+
+// snapshotClient returns the client and URL of the snapshot endpoint.
+func snapshotClient() (*http.Client, string, error) {
+	client, base, err := apiClient()
+	if err != nil {
+		return nil, "", err
+	}
+
+	return client, base + "/v1/snapshot", nil
+}
@@ -31,7 +31,23 @@ type TaskvaultClient interface {
 	DeleteValue(ctx context.Context, in *DeleteValueRequest, opts ...grpc.CallOption) (*DeleteValueResponse, error)
 	RaftGetConfiguration(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*RaftGetConfigurationResponse, error)
 	RaftRemovePeerByID(ctx context.Context, in *RaftRemovePeerByIDRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
-	GetAllPairs(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GetAllPairsResponse, error)
+	RaftTransferLeader(ctx context.Context, in *RaftTransferLeaderRequest, opts ...grpc.CallOption) (*RaftTransferLeaderResponse, error)
+	RaftStats(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*RaftStatsResponse, error)
+	GetAllPairs(ctx context.Context, in *GetAllPairsRequest, opts ...grpc.CallOption) (*GetAllPairsResponse, error)
+	Txn(ctx context.Context, in *TxnRequest, opts ...grpc.CallOption) (*TxnResponse, error)
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (Taskvault_WatchClient, error)
+	SessionCreate(ctx context.Context, in *Session, opts ...grpc.CallOption) (*Session, error)
+	SessionRenew(ctx context.Context, in *SessionRequest, opts ...grpc.CallOption) (*Session, error)
+	SessionDestroy(ctx context.Context, in *SessionRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	QueueEnqueue(ctx context.Context, in *Task, opts ...grpc.CallOption) (*Task, error)
+	QueueClaim(ctx context.Context, in *QueueClaimRequest, opts ...grpc.CallOption) (*QueueClaimResponse, error)
+	QueueAck(ctx context.Context, in *QueueTaskRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	QueueNack(ctx context.Context, in *QueueTaskRequest, opts ...grpc.CallOption) (*Task, error)
+	QueueDelete(ctx context.Context, in *QueueTaskRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	QueueList(ctx context.Context, in *QueueListRequest, opts ...grpc.CallOption) (*QueueListResponse, error)
+	JobSet(ctx context.Context, in *Job, opts ...grpc.CallOption) (*Job, error)
+	JobDelete(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Incr(ctx context.Context, in *IncrRequest, opts ...grpc.CallOption) (*IncrResponse, error)
 }
 
 type taskvaultClient struct {
@@ -105,7 +121,25 @@ func (c *taskvaultClient) RaftRemovePeerByID(ctx context.Context, in *RaftRemove
 	return out, nil
 }
 
-func (c *taskvaultClient) GetAllPairs(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GetAllPairsResponse, error) {
+func (c *taskvaultClient) RaftTransferLeader(ctx context.Context, in *RaftTransferLeaderRequest, opts ...grpc.CallOption) (*RaftTransferLeaderResponse, error) {
+	out := new(RaftTransferLeaderResponse)
+	err := c.cc.Invoke(ctx, "/types.Taskvault/RaftTransferLeader", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskvaultClient) RaftStats(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*RaftStatsResponse, error) {
+	out := new(RaftStatsResponse)
+	err := c.cc.Invoke(ctx, "/types.Taskvault/RaftStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskvaultClient) GetAllPairs(ctx context.Context, in *GetAllPairsRequest, opts ...grpc.CallOption) (*GetAllPairsResponse, error) {
 	out := new(GetAllPairsResponse)
 	err := c.cc.Invoke(ctx, "/types.Taskvault/GetAllPairs", in, out, opts...)
 	if err != nil {
@@ -114,6 +148,155 @@ func (c *taskvaultClient) GetAllPairs(ctx context.Context, in *emptypb.Empty, op
 	return out, nil
 }
 
+func (c *taskvaultClient) Txn(ctx context.Context, in *TxnRequest, opts ...grpc.CallOption) (*TxnResponse, error) {
+	out := new(TxnResponse)
+	err := c.cc.Invoke(ctx, "/types.Taskvault/Txn", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskvaultClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (Taskvault_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Taskvault_ServiceDesc.Streams[0], "/types.Taskvault/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &taskvaultWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Taskvault_WatchClient interface {
+	Recv() (*WatchEvent, error)
+	grpc.ClientStream
+}
+
+type taskvaultWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *taskvaultWatchClient) Recv() (*WatchEvent, error) {
+	m := new(WatchEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *taskvaultClient) SessionCreate(ctx context.Context, in *Session, opts ...grpc.CallOption) (*Session, error) {
+	out := new(Session)
+	err := c.cc.Invoke(ctx, "/types.Taskvault/SessionCreate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskvaultClient) SessionRenew(ctx context.Context, in *SessionRequest, opts ...grpc.CallOption) (*Session, error) {
+	out := new(Session)
+	err := c.cc.Invoke(ctx, "/types.Taskvault/SessionRenew", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskvaultClient) SessionDestroy(ctx context.Context, in *SessionRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/types.Taskvault/SessionDestroy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskvaultClient) QueueEnqueue(ctx context.Context, in *Task, opts ...grpc.CallOption) (*Task, error) {
+	out := new(Task)
+	err := c.cc.Invoke(ctx, "/types.Taskvault/QueueEnqueue", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskvaultClient) QueueClaim(ctx context.Context, in *QueueClaimRequest, opts ...grpc.CallOption) (*QueueClaimResponse, error) {
+	out := new(QueueClaimResponse)
+	err := c.cc.Invoke(ctx, "/types.Taskvault/QueueClaim", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskvaultClient) QueueAck(ctx context.Context, in *QueueTaskRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/types.Taskvault/QueueAck", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskvaultClient) QueueNack(ctx context.Context, in *QueueTaskRequest, opts ...grpc.CallOption) (*Task, error) {
+	out := new(Task)
+	err := c.cc.Invoke(ctx, "/types.Taskvault/QueueNack", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskvaultClient) QueueDelete(ctx context.Context, in *QueueTaskRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/types.Taskvault/QueueDelete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskvaultClient) QueueList(ctx context.Context, in *QueueListRequest, opts ...grpc.CallOption) (*QueueListResponse, error) {
+	out := new(QueueListResponse)
+	err := c.cc.Invoke(ctx, "/types.Taskvault/QueueList", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskvaultClient) JobSet(ctx context.Context, in *Job, opts ...grpc.CallOption) (*Job, error) {
+	out := new(Job)
+	err := c.cc.Invoke(ctx, "/types.Taskvault/JobSet", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskvaultClient) JobDelete(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/types.Taskvault/JobDelete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskvaultClient) Incr(ctx context.Context, in *IncrRequest, opts ...grpc.CallOption) (*IncrResponse, error) {
+	out := new(IncrResponse)
+	err := c.cc.Invoke(ctx, "/types.Taskvault/Incr", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TaskvaultServer is the server API for Taskvault service.
 // All implementations must embed UnimplementedTaskvaultServer
 // for forward compatibility
@@ -125,7 +308,23 @@ type TaskvaultServer interface {
 	DeleteValue(context.Context, *DeleteValueRequest) (*DeleteValueResponse, error)
 	RaftGetConfiguration(context.Context, *emptypb.Empty) (*RaftGetConfigurationResponse, error)
 	RaftRemovePeerByID(context.Context, *RaftRemovePeerByIDRequest) (*emptypb.Empty, error)
-	GetAllPairs(context.Context, *emptypb.Empty) (*GetAllPairsResponse, error)
+	RaftTransferLeader(context.Context, *RaftTransferLeaderRequest) (*RaftTransferLeaderResponse, error)
+	RaftStats(context.Context, *emptypb.Empty) (*RaftStatsResponse, error)
+	GetAllPairs(context.Context, *GetAllPairsRequest) (*GetAllPairsResponse, error)
+	Txn(context.Context, *TxnRequest) (*TxnResponse, error)
+	Watch(*WatchRequest, Taskvault_WatchServer) error
+	SessionCreate(context.Context, *Session) (*Session, error)
+	SessionRenew(context.Context, *SessionRequest) (*Session, error)
+	SessionDestroy(context.Context, *SessionRequest) (*emptypb.Empty, error)
+	QueueEnqueue(context.Context, *Task) (*Task, error)
+	QueueClaim(context.Context, *QueueClaimRequest) (*QueueClaimResponse, error)
+	QueueAck(context.Context, *QueueTaskRequest) (*emptypb.Empty, error)
+	QueueNack(context.Context, *QueueTaskRequest) (*Task, error)
+	QueueDelete(context.Context, *QueueTaskRequest) (*emptypb.Empty, error)
+	QueueList(context.Context, *QueueListRequest) (*QueueListResponse, error)
+	JobSet(context.Context, *Job) (*Job, error)
+	JobDelete(context.Context, *JobRequest) (*emptypb.Empty, error)
+	Incr(context.Context, *IncrRequest) (*IncrResponse, error)
 	mustEmbedUnimplementedTaskvaultServer()
 }
 
@@ -154,9 +353,57 @@ func (UnimplementedTaskvaultServer) RaftGetConfiguration(context.Context, *empty
 func (UnimplementedTaskvaultServer) RaftRemovePeerByID(context.Context, *RaftRemovePeerByIDRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RaftRemovePeerByID not implemented")
 }
-func (UnimplementedTaskvaultServer) GetAllPairs(context.Context, *emptypb.Empty) (*GetAllPairsResponse, error) {
+func (UnimplementedTaskvaultServer) RaftTransferLeader(context.Context, *RaftTransferLeaderRequest) (*RaftTransferLeaderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RaftTransferLeader not implemented")
+}
+func (UnimplementedTaskvaultServer) RaftStats(context.Context, *emptypb.Empty) (*RaftStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RaftStats not implemented")
+}
+func (UnimplementedTaskvaultServer) GetAllPairs(context.Context, *GetAllPairsRequest) (*GetAllPairsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAllPairs not implemented")
 }
+func (UnimplementedTaskvaultServer) Txn(context.Context, *TxnRequest) (*TxnResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Txn not implemented")
+}
+func (UnimplementedTaskvaultServer) Watch(*WatchRequest, Taskvault_WatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedTaskvaultServer) SessionCreate(context.Context, *Session) (*Session, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SessionCreate not implemented")
+}
+func (UnimplementedTaskvaultServer) SessionRenew(context.Context, *SessionRequest) (*Session, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SessionRenew not implemented")
+}
+func (UnimplementedTaskvaultServer) SessionDestroy(context.Context, *SessionRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SessionDestroy not implemented")
+}
+func (UnimplementedTaskvaultServer) QueueEnqueue(context.Context, *Task) (*Task, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueueEnqueue not implemented")
+}
+func (UnimplementedTaskvaultServer) QueueClaim(context.Context, *QueueClaimRequest) (*QueueClaimResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueueClaim not implemented")
+}
+func (UnimplementedTaskvaultServer) QueueAck(context.Context, *QueueTaskRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueueAck not implemented")
+}
+func (UnimplementedTaskvaultServer) QueueNack(context.Context, *QueueTaskRequest) (*Task, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueueNack not implemented")
+}
+func (UnimplementedTaskvaultServer) QueueDelete(context.Context, *QueueTaskRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueueDelete not implemented")
+}
+func (UnimplementedTaskvaultServer) QueueList(context.Context, *QueueListRequest) (*QueueListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueueList not implemented")
+}
+func (UnimplementedTaskvaultServer) JobSet(context.Context, *Job) (*Job, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method JobSet not implemented")
+}
+func (UnimplementedTaskvaultServer) JobDelete(context.Context, *JobRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method JobDelete not implemented")
+}
+func (UnimplementedTaskvaultServer) Incr(context.Context, *IncrRequest) (*IncrResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Incr not implemented")
+}
 func (UnimplementedTaskvaultServer) mustEmbedUnimplementedTaskvaultServer() {}
 
 // UnsafeTaskvaultServer may be embedded to opt out of forward compatibility for this service.
@@ -296,11 +543,47 @@ func _Taskvault_RaftRemovePeerByID_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Taskvault_GetAllPairs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func _Taskvault_RaftTransferLeader_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RaftTransferLeaderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskvaultServer).RaftTransferLeader(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/types.Taskvault/RaftTransferLeader",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskvaultServer).RaftTransferLeader(ctx, req.(*RaftTransferLeaderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Taskvault_RaftStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(emptypb.Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
+	if interceptor == nil {
+		return srv.(TaskvaultServer).RaftStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/types.Taskvault/RaftStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskvaultServer).RaftStats(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Taskvault_GetAllPairs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAllPairsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
 	if interceptor == nil {
 		return srv.(TaskvaultServer).GetAllPairs(ctx, in)
 	}
@@ -309,7 +592,262 @@ func _Taskvault_GetAllPairs_Handler(srv interface{}, ctx context.Context, dec fu
 		FullMethod: "/types.Taskvault/GetAllPairs",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(TaskvaultServer).GetAllPairs(ctx, req.(*emptypb.Empty))
+		return srv.(TaskvaultServer).GetAllPairs(ctx, req.(*GetAllPairsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Taskvault_Txn_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TxnRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskvaultServer).Txn(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/types.Taskvault/Txn",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskvaultServer).Txn(ctx, req.(*TxnRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Taskvault_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TaskvaultServer).Watch(m, &taskvaultWatchServer{stream})
+}
+
+type Taskvault_WatchServer interface {
+	Send(*WatchEvent) error
+	grpc.ServerStream
+}
+
+type taskvaultWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *taskvaultWatchServer) Send(m *WatchEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Taskvault_SessionCreate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Session)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskvaultServer).SessionCreate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/types.Taskvault/SessionCreate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskvaultServer).SessionCreate(ctx, req.(*Session))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Taskvault_SessionRenew_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskvaultServer).SessionRenew(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/types.Taskvault/SessionRenew",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskvaultServer).SessionRenew(ctx, req.(*SessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Taskvault_SessionDestroy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskvaultServer).SessionDestroy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/types.Taskvault/SessionDestroy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskvaultServer).SessionDestroy(ctx, req.(*SessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Taskvault_QueueEnqueue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Task)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskvaultServer).QueueEnqueue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/types.Taskvault/QueueEnqueue",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskvaultServer).QueueEnqueue(ctx, req.(*Task))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Taskvault_QueueClaim_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueueClaimRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskvaultServer).QueueClaim(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/types.Taskvault/QueueClaim",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskvaultServer).QueueClaim(ctx, req.(*QueueClaimRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Taskvault_QueueAck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueueTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskvaultServer).QueueAck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/types.Taskvault/QueueAck",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskvaultServer).QueueAck(ctx, req.(*QueueTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Taskvault_QueueNack_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueueTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskvaultServer).QueueNack(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/types.Taskvault/QueueNack",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskvaultServer).QueueNack(ctx, req.(*QueueTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Taskvault_QueueDelete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueueTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskvaultServer).QueueDelete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/types.Taskvault/QueueDelete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskvaultServer).QueueDelete(ctx, req.(*QueueTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Taskvault_QueueList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueueListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskvaultServer).QueueList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/types.Taskvault/QueueList",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskvaultServer).QueueList(ctx, req.(*QueueListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Taskvault_JobSet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Job)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskvaultServer).JobSet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/types.Taskvault/JobSet",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskvaultServer).JobSet(ctx, req.(*Job))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Taskvault_JobDelete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskvaultServer).JobDelete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/types.Taskvault/JobDelete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskvaultServer).JobDelete(ctx, req.(*JobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Taskvault_Incr_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IncrRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskvaultServer).Incr(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/types.Taskvault/Incr",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskvaultServer).Incr(ctx, req.(*IncrRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -349,11 +887,77 @@ var Taskvault_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RaftRemovePeerByID",
 			Handler:    _Taskvault_RaftRemovePeerByID_Handler,
 		},
+		{
+			MethodName: "RaftTransferLeader",
+			Handler:    _Taskvault_RaftTransferLeader_Handler,
+		},
+		{
+			MethodName: "RaftStats",
+			Handler:    _Taskvault_RaftStats_Handler,
+		},
 		{
 			MethodName: "GetAllPairs",
 			Handler:    _Taskvault_GetAllPairs_Handler,
 		},
+		{
+			MethodName: "Txn",
+			Handler:    _Taskvault_Txn_Handler,
+		},
+		{
+			MethodName: "SessionCreate",
+			Handler:    _Taskvault_SessionCreate_Handler,
+		},
+		{
+			MethodName: "SessionRenew",
+			Handler:    _Taskvault_SessionRenew_Handler,
+		},
+		{
+			MethodName: "SessionDestroy",
+			Handler:    _Taskvault_SessionDestroy_Handler,
+		},
+		{
+			MethodName: "QueueEnqueue",
+			Handler:    _Taskvault_QueueEnqueue_Handler,
+		},
+		{
+			MethodName: "QueueClaim",
+			Handler:    _Taskvault_QueueClaim_Handler,
+		},
+		{
+			MethodName: "QueueAck",
+			Handler:    _Taskvault_QueueAck_Handler,
+		},
+		{
+			MethodName: "QueueNack",
+			Handler:    _Taskvault_QueueNack_Handler,
+		},
+		{
+			MethodName: "QueueDelete",
+			Handler:    _Taskvault_QueueDelete_Handler,
+		},
+		{
+			MethodName: "QueueList",
+			Handler:    _Taskvault_QueueList_Handler,
+		},
+		{
+			MethodName: "JobSet",
+			Handler:    _Taskvault_JobSet_Handler,
+		},
+		{
+			MethodName: "JobDelete",
+			Handler:    _Taskvault_JobDelete_Handler,
+		},
+		{
+			MethodName: "Incr",
+			Handler:    _Taskvault_Incr_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _Taskvault_Watch_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "taskvault.proto",
 }
@@ -0,0 +1,347 @@
+package taskvault
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/danluki/taskvault/pkg/types"
+	"google.golang.org/grpc/metadata"
+)
+
+// Access levels of ACL rules. Where several rules match equally well, deny
+// wins over write and write over read.
+const (
+	ACLRead  = "read"
+	ACLWrite = "write"
+	ACLDeny  = "deny"
+)
+
+// Default policies, see Config.ACLDefaultPolicy.
+const (
+	ACLPolicyAllow = "allow"
+	ACLPolicyDeny  = "deny"
+)
+
+// aclTokenKey carries the secret of the caller's token in gRPC metadata.
+const aclTokenKey = "x-taskvault-token"
+
+var (
+	ErrPermissionDenied = errors.New("permission denied")
+	ErrACLNotFound      = errors.New("acl not found")
+	ErrACLBootstrapped  = errors.New("acl bootstrap already done")
+)
+
+// ACLRule grants Access to the keys starting with Prefix.
+type ACLRule struct {
+	Prefix string
+	Access string
+}
+
+// ACLPolicy is a named set of rules that tokens refer to.
+type ACLPolicy struct {
+	Name        string
+	Description string
+	Rules       []ACLRule
+	// Operator and Agent are the access to the operator and agent
+	// endpoints, empty when the policy grants none.
+	Operator    string
+	Agent       string
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ACLToken identifies a client. AccessorID is safe to log and show, the
+// client authenticates with SecretID.
+type ACLToken struct {
+	AccessorID  string
+	SecretID    string
+	Description string
+	Policies    []string
+	// Management tokens are granted everything.
+	Management  bool
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+func aclTokenFromProto(t *types.ACLToken) *ACLToken {
+	return &ACLToken{
+		AccessorID:  t.AccessorId,
+		SecretID:    t.SecretId,
+		Description: t.Description,
+		Policies:    t.Policies,
+		Management:  t.Management,
+	}
+}
+
+func (t *ACLToken) proto() *types.ACLToken {
+	return &types.ACLToken{
+		AccessorId:  t.AccessorID,
+		SecretId:    t.SecretID,
+		Description: t.Description,
+		Policies:    t.Policies,
+		Management:  t.Management,
+	}
+}
+
+func aclPolicyFromProto(p *types.ACLPolicy) *ACLPolicy {
+	policy := &ACLPolicy{
+		Name:        p.Name,
+		Description: p.Description,
+		Operator:    p.Operator,
+		Agent:       p.Agent,
+	}
+	for _, r := range p.Rules {
+		policy.Rules = append(policy.Rules, ACLRule{Prefix: r.Prefix, Access: r.Access})
+	}
+
+	return policy
+}
+
+func (p *ACLPolicy) proto() *types.ACLPolicy {
+	policy := &types.ACLPolicy{
+		Name:        p.Name,
+		Description: p.Description,
+		Operator:    p.Operator,
+		Agent:       p.Agent,
+	}
+	for _, r := range p.Rules {
+		policy.Rules = append(policy.Rules, &types.ACLRule{Prefix: r.Prefix, Access: r.Access})
+	}
+
+	return policy
+}
+
+// validate checks that the policy is named and only uses known access
+// levels.
+func (p *ACLPolicy) validate() error {
+	if p.Name == "" {
+		return errors.New("policy name is required")
+	}
+
+	valid := func(access string, optional bool) bool {
+		switch access {
+		case ACLRead, ACLWrite, ACLDeny:
+			return true
+		case "":
+			return optional
+		}
+		return false
+	}
+	for _, r := range p.Rules {
+		if !valid(r.Access, false) {
+			return fmt.Errorf("unknown access %q for prefix %q", r.Access, r.Prefix)
+		}
+	}
+	if !valid(p.Operator, true) {
+		return fmt.Errorf("unknown operator access %q", p.Operator)
+	}
+	if !valid(p.Agent, true) {
+		return fmt.Errorf("unknown agent access %q", p.Agent)
+	}
+
+	return nil
+}
+
+// aclAuthorizer decides what a token may do. A nil authorizer allows
+// everything, it is what requests get while ACLs are disabled.
+type aclAuthorizer struct {
+	management bool
+	rules      []ACLRule
+	operator   string
+	agent      string
+	// fallback is the access where no rule applies.
+	fallback string
+}
+
+func newACLAuthorizer(defaultPolicy string, policies []*ACLPolicy) *aclAuthorizer {
+	a := &aclAuthorizer{fallback: ACLDeny}
+	if defaultPolicy == ACLPolicyAllow {
+		a.fallback = ACLWrite
+	}
+
+	for _, p := range policies {
+		a.rules = append(a.rules, p.Rules...)
+		a.operator = strongerAccess(a.operator, p.Operator)
+		a.agent = strongerAccess(a.agent, p.Agent)
+	}
+
+	return a
+}
+
+// strongerAccess returns the access that wins when both apply.
+func strongerAccess(a, b string) string {
+	rank := map[string]int{"": 0, ACLRead: 1, ACLWrite: 2, ACLDeny: 3}
+	if rank[b] > rank[a] {
+		return b
+	}
+	return a
+}
+
+// keyAccess returns the access of the rule with the longest prefix of key.
+func (a *aclAuthorizer) keyAccess(key string) string {
+	access, longest := "", -1
+	for _, r := range a.rules {
+		if !strings.HasPrefix(key, r.Prefix) {
+			continue
+		}
+		switch {
+		case len(r.Prefix) > longest:
+			access, longest = r.Access, len(r.Prefix)
+		case len(r.Prefix) == longest:
+			access = strongerAccess(access, r.Access)
+		}
+	}
+
+	return access
+}
+
+func (a *aclAuthorizer) allows(access, want string) bool {
+	if access == "" {
+		access = a.fallback
+	}
+
+	switch want {
+	case ACLRead:
+		return access == ACLRead || access == ACLWrite
+	case ACLWrite:
+		return access == ACLWrite
+	}
+
+	return false
+}
+
+func (a *aclAuthorizer) KeyRead(key string) bool {
+	return a == nil || a.management || a.allows(a.keyAccess(key), ACLRead)
+}
+
+func (a *aclAuthorizer) KeyWrite(key string) bool {
+	return a == nil || a.management || a.allows(a.keyAccess(key), ACLWrite)
+}
+
+func (a *aclAuthorizer) OperatorRead() bool {
+	return a == nil || a.management || a.allows(a.operator, ACLRead)
+}
+
+func (a *aclAuthorizer) OperatorWrite() bool {
+	return a == nil || a.management || a.allows(a.operator, ACLWrite)
+}
+
+func (a *aclAuthorizer) AgentRead() bool {
+	return a == nil || a.management || a.allows(a.agent, ACLRead)
+}
+
+func (a *aclAuthorizer) AgentWrite() bool {
+	return a == nil || a.management || a.allows(a.agent, ACLWrite)
+}
+
+// Management reports whether the token may manage ACLs and snapshots.
+func (a *aclAuthorizer) Management() bool {
+	return a == nil || a.management
+}
+
+// resolveToken returns the authorizer of the token with the given secret,
+// nil when ACLs are disabled. Requests without a token get the default
+// policy, unknown tokens are denied. ACLs are read from the local store,
+// which every server replicates.
+func (a *Agent) resolveToken(secret string) (*aclAuthorizer, error) {
+	if !a.config.ACLEnabled {
+		return nil, nil
+	}
+	if secret == "" {
+		return newACLAuthorizer(a.config.ACLDefaultPolicy, nil), nil
+	}
+	if a.isAgentToken(secret) {
+		return newACLAuthorizer(a.config.ACLDefaultPolicy, []*ACLPolicy{{Operator: ACLRead}}), nil
+	}
+
+	token, err := a.Store.ACLTokenBySecret(secret)
+	if errors.Is(err, ErrACLNotFound) {
+		return nil, fmt.Errorf("%w: acl token not found", ErrPermissionDenied)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if token.Management {
+		return &aclAuthorizer{management: true}, nil
+	}
+
+	var policies []*ACLPolicy
+	for _, name := range token.Policies {
+		policy, err := a.Store.ACLGetPolicy(name)
+		if errors.Is(err, ErrACLNotFound) {
+			// Deleted policies no longer grant anything.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, policy)
+	}
+
+	return newACLAuthorizer(a.config.ACLDefaultPolicy, policies), nil
+}
+
+// serverClient returns the client servers call each other with, it sends
+// the agent token.
+func (a *Agent) serverClient() TaskvaultGRPCClient {
+	return a.GRPCClient.WithToken(a.config.ACLAgentToken)
+}
+
+// requestClient returns the client a request is passed on to the leader
+// with. It sends the token of the request and gives up when the request is
+// canceled.
+func (a *Agent) requestClient(ctx context.Context) TaskvaultGRPCClient {
+	return a.GRPCClient.WithToken(aclTokenFromContext(ctx)).WithContext(ctx)
+}
+
+// isAgentToken reports whether secret is the agent token. It is shared by
+// the servers rather than stored, so they can call each other while the
+// cluster bootstraps and no token exists yet.
+func (a *Agent) isAgentToken(secret string) bool {
+	agent := a.config.ACLAgentToken
+	return agent != "" && subtle.ConstantTimeCompare([]byte(secret), []byte(agent)) == 1
+}
+
+type aclContextKey struct{}
+
+// aclIdentity is what a request authenticated as. The token is passed on
+// when the request is forwarded to the leader.
+type aclIdentity struct {
+	token string
+	authz *aclAuthorizer
+}
+
+func withACL(ctx context.Context, token string, authz *aclAuthorizer) context.Context {
+	return context.WithValue(ctx, aclContextKey{}, &aclIdentity{token: token, authz: authz})
+}
+
+// aclFromContext returns the authorizer of the request, nil for internal
+// calls and while ACLs are disabled.
+func aclFromContext(ctx context.Context) *aclAuthorizer {
+	if id, ok := ctx.Value(aclContextKey{}).(*aclIdentity); ok {
+		return id.authz
+	}
+	return nil
+}
+
+func aclTokenFromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(aclContextKey{}).(*aclIdentity); ok {
+		return id.token
+	}
+	return ""
+}
+
+// aclTokenFromMetadata returns the token sent by a gRPC client.
+func aclTokenFromMetadata(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if v := md.Get(aclTokenKey); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
@@ -0,0 +1,337 @@
+package taskvault
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/danluki/taskvault/pkg/types"
+	"github.com/gin-gonic/gin"
+	"github.com/hashicorp/go-uuid"
+)
+
+// tokenHeader carries the secret of the caller's ACL token, a bearer token
+// in the Authorization header is accepted as well.
+const tokenHeader = "X-Taskvault-Token"
+
+var (
+	errACLDisabled = errors.New("acls are disabled")
+	errInvalidACL  = errors.New("invalid acl")
+)
+
+// aclMiddleware resolves the ACL token of the request and checks the
+// permissions of the route. Storage, queue and job routes are checked key
+// by key where the keys are read, and by the leader for writes.
+func (h *HTTPTransport) aclMiddleware(c *gin.Context) {
+	token := requestToken(c.Request)
+	authz, err := h.agent.resolveToken(token)
+	if err != nil {
+		h.renderReadError(c, err)
+		return
+	}
+	if !routeAllowed(authz, c.Request.Method, c.FullPath()) {
+		_ = c.AbortWithError(http.StatusForbidden, ErrPermissionDenied)
+		return
+	}
+
+	c.Request = c.Request.WithContext(withACL(c.Request.Context(), token, authz))
+	c.Next()
+}
+
+func requestToken(r *http.Request) string {
+	if token := r.Header.Get(tokenHeader); token != "" {
+		return token
+	}
+
+	auth := r.Header.Get("Authorization")
+	if token, ok := strings.CutPrefix(auth, "Bearer "); ok {
+		return strings.TrimSpace(token)
+	}
+
+	return ""
+}
+
+// routeAllowed checks the permissions a route of the HTTP API needs.
+// Leaving the cluster is an operator write, sessions are tied to nodes and
+// need agent access. Snapshots, ACL management and unknown routes need a
+// management token.
+func routeAllowed(authz *aclAuthorizer, method string, route string) bool {
+	p := strings.TrimPrefix(route, "/"+apiPathPrefix)
+
+	switch {
+	case p == "/leader", p == "/isleader", p == "/acl/bootstrap":
+		return true
+	case p == "/storage", strings.HasPrefix(p, "/storage/"), p == "/txn":
+		return true
+	case p == "/queues", strings.HasPrefix(p, "/queues/"):
+		return true
+	case p == "/jobs", strings.HasPrefix(p, "/jobs/"):
+		return true
+	case p == "", p == "/", p == "/members":
+		return authz.AgentRead()
+	case p == "/leave", strings.HasPrefix(p, "/operator/"):
+		if method == http.MethodGet {
+			return authz.OperatorRead()
+		}
+		return authz.OperatorWrite()
+	case p == "/sessions", strings.HasPrefix(p, "/session/"), p == "/session":
+		if method == http.MethodGet {
+			return authz.AgentRead()
+		}
+		return authz.AgentWrite()
+	}
+
+	return authz.Management()
+}
+
+func (h *HTTPTransport) aclEnabledMiddleware(c *gin.Context) {
+	if !h.agent.config.ACLEnabled {
+		_ = c.AbortWithError(http.StatusBadRequest, errACLDisabled)
+		return
+	}
+	c.Next()
+}
+
+// aclBootstrapHandler creates the first management token. It only works
+// once, the token is how operators create every other token.
+func (h *HTTPTransport) aclBootstrapHandler(c *gin.Context) {
+	if h.forwardToLeader(c) {
+		return
+	}
+
+	token, err := newACLToken(&ACLToken{
+		Description: "Bootstrap Token (Global Management)",
+		Management:  true,
+	})
+	if err != nil {
+		h.renderACLError(c, err)
+		return
+	}
+
+	res, err := h.agent.raftApply(ACLBootstrapType, token.proto())
+	if err != nil {
+		h.renderACLError(c, err)
+		return
+	}
+
+	renderJSON(c, http.StatusOK, res)
+}
+
+func (h *HTTPTransport) aclTokenListHandler(c *gin.Context) {
+	tokens, err := h.agent.Store.ACLListTokens()
+	if err != nil {
+		h.renderACLError(c, err)
+		return
+	}
+
+	// Secrets are only shown when a single token is read.
+	for i := range tokens {
+		tokens[i].SecretID = ""
+	}
+	if tokens == nil {
+		tokens = []ACLToken{}
+	}
+	renderJSON(c, http.StatusOK, tokens)
+}
+
+func (h *HTTPTransport) aclTokenGetHandler(c *gin.Context) {
+	token, err := h.agent.Store.ACLGetToken(c.Param("accessor"))
+	if err != nil {
+		h.renderACLError(c, err)
+		return
+	}
+
+	renderJSON(c, http.StatusOK, token)
+}
+
+// aclTokenCreateHandler creates a token from the Description, Policies and
+// Management fields of the body. Its IDs are generated.
+func (h *HTTPTransport) aclTokenCreateHandler(c *gin.Context) {
+	if h.forwardToLeader(c) {
+		return
+	}
+
+	body := &ACLToken{}
+	if err := c.ShouldBindJSON(body); err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+	if err := h.checkPolicies(body.Policies); err != nil {
+		h.renderACLError(c, err)
+		return
+	}
+
+	token, err := newACLToken(body)
+	if err != nil {
+		h.renderACLError(c, err)
+		return
+	}
+
+	res, err := h.agent.raftApply(ACLTokenSetType, token.proto())
+	if err != nil {
+		h.renderACLError(c, err)
+		return
+	}
+
+	renderJSON(c, http.StatusOK, res)
+}
+
+// aclTokenUpdateHandler replaces the Description, Policies and Management
+// fields of a token. Its secret stays the same.
+func (h *HTTPTransport) aclTokenUpdateHandler(c *gin.Context) {
+	if h.forwardToLeader(c) {
+		return
+	}
+
+	body := &ACLToken{}
+	if err := c.ShouldBindJSON(body); err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+	if err := h.checkPolicies(body.Policies); err != nil {
+		h.renderACLError(c, err)
+		return
+	}
+
+	token, err := h.agent.Store.ACLGetToken(c.Param("accessor"))
+	if err != nil {
+		h.renderACLError(c, err)
+		return
+	}
+	token.Description = body.Description
+	token.Policies = body.Policies
+	token.Management = body.Management
+
+	res, err := h.agent.raftApply(ACLTokenSetType, token.proto())
+	if err != nil {
+		h.renderACLError(c, err)
+		return
+	}
+
+	renderJSON(c, http.StatusOK, res)
+}
+
+func (h *HTTPTransport) aclTokenDeleteHandler(c *gin.Context) {
+	if h.forwardToLeader(c) {
+		return
+	}
+
+	_, err := h.agent.raftApply(ACLTokenDeleteType, &types.ACLDeleteRequest{
+		Id: c.Param("accessor"),
+	})
+	if err != nil {
+		h.renderACLError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (h *HTTPTransport) aclPolicyListHandler(c *gin.Context) {
+	policies, err := h.agent.Store.ACLListPolicies()
+	if err != nil {
+		h.renderACLError(c, err)
+		return
+	}
+
+	if policies == nil {
+		policies = []ACLPolicy{}
+	}
+	renderJSON(c, http.StatusOK, policies)
+}
+
+func (h *HTTPTransport) aclPolicyGetHandler(c *gin.Context) {
+	policy, err := h.agent.Store.ACLGetPolicy(c.Param("name"))
+	if err != nil {
+		h.renderACLError(c, err)
+		return
+	}
+
+	renderJSON(c, http.StatusOK, policy)
+}
+
+// aclPolicySetHandler creates or replaces the policy named in the path.
+func (h *HTTPTransport) aclPolicySetHandler(c *gin.Context) {
+	if h.forwardToLeader(c) {
+		return
+	}
+
+	policy := &ACLPolicy{}
+	if err := c.ShouldBindJSON(policy); err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+	policy.Name = c.Param("name")
+	if err := policy.validate(); err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	res, err := h.agent.raftApply(ACLPolicySetType, policy.proto())
+	if err != nil {
+		h.renderACLError(c, err)
+		return
+	}
+
+	renderJSON(c, http.StatusOK, res)
+}
+
+func (h *HTTPTransport) aclPolicyDeleteHandler(c *gin.Context) {
+	if h.forwardToLeader(c) {
+		return
+	}
+
+	_, err := h.agent.raftApply(ACLPolicyDeleteType, &types.ACLDeleteRequest{
+		Id: c.Param("name"),
+	})
+	if err != nil {
+		h.renderACLError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// checkPolicies makes sure every named policy exists.
+func (h *HTTPTransport) checkPolicies(names []string) error {
+	for _, name := range names {
+		if _, err := h.agent.Store.ACLGetPolicy(name); err != nil {
+			if errors.Is(err, ErrACLNotFound) {
+				return fmt.Errorf("%w: unknown policy %q", errInvalidACL, name)
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (h *HTTPTransport) renderACLError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrACLNotFound):
+		_ = c.AbortWithError(http.StatusNotFound, err)
+	case errors.Is(err, ErrACLBootstrapped):
+		_ = c.AbortWithError(http.StatusConflict, err)
+	case errors.Is(err, errInvalidACL):
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+	default:
+		h.renderWriteError(c, err)
+	}
+}
+
+// newACLToken returns a copy of token with new accessor and secret IDs.
+func newACLToken(token *ACLToken) (*ACLToken, error) {
+	accessor, err := uuid.GenerateUUID()
+	if err != nil {
+		return nil, err
+	}
+	secret, err := uuid.GenerateUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	t := *token
+	t.AccessorID, t.SecretID = accessor, secret
+	return &t, nil
+}
@@ -0,0 +1,306 @@
+package taskvault
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tidwall/buntdb"
+)
+
+// ACLs live in the reserved key space so they are replicated and
+// snapshotted with the data but never visible to clients.
+const (
+	aclTokenPrefix  = reservedPrefix + "acl/token/"
+	aclSecretPrefix = reservedPrefix + "acl/secret/"
+	aclPolicyPrefix = reservedPrefix + "acl/policy/"
+	aclBootstrapKey = reservedPrefix + "acl/bootstrap"
+)
+
+// errRecordNotFound is returned by getJSON for a missing record. Callers
+// turn it into the not found error of the record they read.
+var errRecordNotFound = errors.New("record not found")
+
+func getJSON(tx *buntdb.Tx, key string, v any) error {
+	raw, err := tx.Get(key)
+	if errors.Is(err, buntdb.ErrNotFound) {
+		return errRecordNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal([]byte(raw), v); err != nil {
+		return fmt.Errorf("%w: %s", errEntryCorrupt, err)
+	}
+
+	return nil
+}
+
+// getACL reads the token or policy stored under key.
+func getACL(tx *buntdb.Tx, key string, v any) error {
+	err := getJSON(tx, key, v)
+	if errors.Is(err, errRecordNotFound) {
+		return ErrACLNotFound
+	}
+
+	return err
+}
+
+func setJSON(tx *buntdb.Tx, key string, v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = tx.Set(key, string(b), nil)
+	return err
+}
+
+// ACLBootstrap stores the first management token. It fails with
+// ErrACLBootstrapped once a bootstrap was done, even if that token has
+// been deleted since.
+func (s *Store) ACLBootstrap(token *ACLToken, index uint64) (*ACLToken, error) {
+	var stored *ACLToken
+
+	err := s.db.Update(func(tx *buntdb.Tx) error {
+		if _, err := tx.Get(aclBootstrapKey); err == nil {
+			return ErrACLBootstrapped
+		} else if !errors.Is(err, buntdb.ErrNotFound) {
+			return err
+		}
+
+		var err error
+		stored, err = putACLToken(tx, token, index)
+		if err != nil {
+			return err
+		}
+		if _, _, err := tx.Set(aclBootstrapKey, token.AccessorID, nil); err != nil {
+			return err
+		}
+
+		return setAppliedIndex(tx, index)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return stored, nil
+}
+
+// ACLSetToken creates or updates a token. The secret of an existing token
+// never changes.
+func (s *Store) ACLSetToken(token *ACLToken, index uint64) (*ACLToken, error) {
+	var stored *ACLToken
+
+	err := s.db.Update(func(tx *buntdb.Tx) error {
+		var err error
+		stored, err = putACLToken(tx, token, index)
+		if err != nil {
+			return err
+		}
+
+		return setAppliedIndex(tx, index)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return stored, nil
+}
+
+func putACLToken(tx *buntdb.Tx, token *ACLToken, index uint64) (*ACLToken, error) {
+	if token.AccessorID == "" {
+		return nil, errors.New("token accessor id is required")
+	}
+
+	t := *token
+	t.CreateIndex, t.ModifyIndex = index, index
+
+	var current ACLToken
+	err := getACL(tx, aclTokenPrefix+t.AccessorID, &current)
+	switch {
+	case err == nil:
+		t.CreateIndex = current.CreateIndex
+		t.SecretID = current.SecretID
+	case !errors.Is(err, ErrACLNotFound):
+		return nil, err
+	case t.SecretID == "":
+		return nil, errors.New("token secret id is required")
+	default:
+		if _, err := tx.Get(aclSecretPrefix + t.SecretID); err == nil {
+			return nil, errors.New("token secret id is already in use")
+		}
+	}
+
+	if err := setJSON(tx, aclTokenPrefix+t.AccessorID, &t); err != nil {
+		return nil, err
+	}
+	if _, _, err := tx.Set(aclSecretPrefix+t.SecretID, t.AccessorID, nil); err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+func (s *Store) ACLDeleteToken(accessor string, index uint64) error {
+	return s.db.Update(func(tx *buntdb.Tx) error {
+		var token ACLToken
+		if err := getACL(tx, aclTokenPrefix+accessor, &token); err != nil {
+			return err
+		}
+
+		if _, err := tx.Delete(aclTokenPrefix + accessor); err != nil {
+			return err
+		}
+		if _, err := tx.Delete(aclSecretPrefix + token.SecretID); err != nil &&
+			!errors.Is(err, buntdb.ErrNotFound) {
+			return err
+		}
+
+		return setAppliedIndex(tx, index)
+	})
+}
+
+func (s *Store) ACLGetToken(accessor string) (*ACLToken, error) {
+	var token ACLToken
+
+	err := s.db.View(func(tx *buntdb.Tx) error {
+		return getACL(tx, aclTokenPrefix+accessor, &token)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// ACLTokenBySecret looks a token up by the secret clients send.
+func (s *Store) ACLTokenBySecret(secret string) (*ACLToken, error) {
+	var token ACLToken
+
+	err := s.db.View(func(tx *buntdb.Tx) error {
+		accessor, err := tx.Get(aclSecretPrefix + secret)
+		if errors.Is(err, buntdb.ErrNotFound) {
+			return ErrACLNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		return getACL(tx, aclTokenPrefix+accessor, &token)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+func (s *Store) ACLListTokens() ([]ACLToken, error) {
+	var tokens []ACLToken
+	err := s.db.View(func(tx *buntdb.Tx) error {
+		return listJSON(tx, aclTokenPrefix, func(raw string) error {
+			var token ACLToken
+			if err := json.Unmarshal([]byte(raw), &token); err != nil {
+				return err
+			}
+			tokens = append(tokens, token)
+			return nil
+		})
+	})
+
+	return tokens, err
+}
+
+func (s *Store) ACLSetPolicy(policy *ACLPolicy, index uint64) (*ACLPolicy, error) {
+	p := *policy
+	p.CreateIndex, p.ModifyIndex = index, index
+
+	err := s.db.Update(func(tx *buntdb.Tx) error {
+		var current ACLPolicy
+		err := getACL(tx, aclPolicyPrefix+p.Name, &current)
+		if err == nil {
+			p.CreateIndex = current.CreateIndex
+		} else if !errors.Is(err, ErrACLNotFound) {
+			return err
+		}
+
+		if err := setJSON(tx, aclPolicyPrefix+p.Name, &p); err != nil {
+			return err
+		}
+
+		return setAppliedIndex(tx, index)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// ACLDeletePolicy removes a policy. Tokens still naming it are left alone,
+// the policy simply no longer grants them anything.
+func (s *Store) ACLDeletePolicy(name string, index uint64) error {
+	return s.db.Update(func(tx *buntdb.Tx) error {
+		if _, err := tx.Delete(aclPolicyPrefix + name); err != nil {
+			if errors.Is(err, buntdb.ErrNotFound) {
+				return ErrACLNotFound
+			}
+			return err
+		}
+
+		return setAppliedIndex(tx, index)
+	})
+}
+
+func (s *Store) ACLGetPolicy(name string) (*ACLPolicy, error) {
+	var policy ACLPolicy
+
+	err := s.db.View(func(tx *buntdb.Tx) error {
+		return getACL(tx, aclPolicyPrefix+name, &policy)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &policy, nil
+}
+
+func (s *Store) ACLListPolicies() ([]ACLPolicy, error) {
+	var policies []ACLPolicy
+	err := s.db.View(func(tx *buntdb.Tx) error {
+		return listJSON(tx, aclPolicyPrefix, func(raw string) error {
+			var policy ACLPolicy
+			if err := json.Unmarshal([]byte(raw), &policy); err != nil {
+				return err
+			}
+			policies = append(policies, policy)
+			return nil
+		})
+	})
+
+	return policies, err
+}
+
+// listJSON calls fn with the value of every key starting with prefix, in
+// key order.
+func listJSON(tx *buntdb.Tx, prefix string, fn func(raw string) error) error {
+	var fnErr error
+	err := tx.AscendGreaterOrEqual("", prefix, func(k, v string) bool {
+		if !strings.HasPrefix(k, prefix) {
+			return false
+		}
+		fnErr = fn(v)
+		return fnErr == nil
+	})
+	if err != nil {
+		return err
+	}
+	if fnErr != nil {
+		return fmt.Errorf("%w: %s", errEntryCorrupt, fnErr)
+	}
+
+	return nil
+}
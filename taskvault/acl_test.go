@@ -0,0 +1,180 @@
+package taskvault
+
+import (
+	"net/http"
+	"testing"
+
+	types2 "github.com/danluki/taskvault/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLAuthorizer(t *testing.T) {
+	authz := newACLAuthorizer(ACLPolicyDeny, []*ACLPolicy{
+		{
+			Rules: []ACLRule{
+				{Prefix: "", Access: ACLRead},
+				{Prefix: "app/", Access: ACLWrite},
+				{Prefix: "app/secret", Access: ACLDeny},
+			},
+			Operator: ACLRead,
+		},
+		{
+			Rules: []ACLRule{{Prefix: "app/", Access: ACLRead}},
+			Agent: ACLWrite,
+		},
+	})
+
+	assert.True(t, authz.KeyRead("other"))
+	assert.False(t, authz.KeyWrite("other"))
+	assert.True(t, authz.KeyWrite("app/config"))
+	assert.False(t, authz.KeyRead("app/secret/key"))
+	assert.True(t, authz.OperatorRead())
+	assert.False(t, authz.OperatorWrite())
+	assert.True(t, authz.AgentWrite())
+	assert.False(t, authz.Management())
+
+	anonymous := newACLAuthorizer(ACLPolicyDeny, nil)
+	assert.False(t, anonymous.KeyRead("app/config"))
+	assert.False(t, anonymous.AgentRead())
+
+	open := newACLAuthorizer(ACLPolicyAllow, []*ACLPolicy{
+		{Rules: []ACLRule{{Prefix: "locked/", Access: ACLDeny}}},
+	})
+	assert.True(t, open.KeyWrite("app/config"))
+	assert.False(t, open.KeyRead("locked/key"))
+	assert.True(t, open.OperatorWrite())
+
+	var disabled *aclAuthorizer
+	assert.True(t, disabled.KeyWrite("locked/key"))
+	assert.True(t, disabled.Management())
+}
+
+func TestACLPolicy_Validate(t *testing.T) {
+	assert.Error(t, (&ACLPolicy{}).validate())
+	assert.Error(t, (&ACLPolicy{
+		Name:  "p",
+		Rules: []ACLRule{{Prefix: "a", Access: "all"}},
+	}).validate())
+	assert.Error(t, (&ACLPolicy{Name: "p", Operator: "all"}).validate())
+	assert.NoError(t, (&ACLPolicy{
+		Name:  "p",
+		Rules: []ACLRule{{Prefix: "a", Access: ACLWrite}},
+		Agent: ACLRead,
+	}).validate())
+}
+
+func TestRouteAllowed(t *testing.T) {
+	anonymous := newACLAuthorizer(ACLPolicyDeny, nil)
+	operator := newACLAuthorizer(ACLPolicyDeny, []*ACLPolicy{{Operator: ACLRead}})
+
+	assert.True(t, routeAllowed(anonymous, http.MethodPost, "/v1/acl/bootstrap"))
+	assert.True(t, routeAllowed(anonymous, http.MethodGet, "/v1/storage/*key"))
+	assert.True(t, routeAllowed(anonymous, http.MethodPost, "/v1/queues/:name/claim"))
+	assert.False(t, routeAllowed(anonymous, http.MethodGet, "/v1"))
+	assert.False(t, routeAllowed(anonymous, http.MethodGet, "/v1/"))
+	assert.False(t, routeAllowed(anonymous, http.MethodGet, "/v1/members"))
+	assert.False(t, routeAllowed(anonymous, http.MethodGet, "/v1/snapshot"))
+	assert.True(t, routeAllowed(operator, http.MethodGet, "/v1/operator/autopilot/health"))
+	assert.False(t, routeAllowed(operator, http.MethodPost, "/v1/session"))
+	assert.False(t, routeAllowed(operator, http.MethodPost, "/v1/operator/raft/transfer-leader"))
+	assert.False(t, routeAllowed(operator, http.MethodPost, "/v1/leave"))
+	assert.False(t, routeAllowed(newACLAuthorizer(ACLPolicyDeny, []*ACLPolicy{{Agent: ACLWrite}}),
+		http.MethodPost, "/v1/leave"))
+	assert.True(t, routeAllowed(newACLAuthorizer(ACLPolicyDeny, []*ACLPolicy{{Operator: ACLWrite}}),
+		http.MethodPost, "/v1/leave"))
+	assert.False(t, routeAllowed(operator, http.MethodGet, "/v1/acl/tokens"))
+	assert.True(t, routeAllowed(&aclAuthorizer{management: true}, http.MethodGet, "/v1/acl/tokens"))
+}
+
+func TestRPCAllowed(t *testing.T) {
+	anonymous := newACLAuthorizer(ACLPolicyDeny, nil)
+	operator := newACLAuthorizer(ACLPolicyDeny, []*ACLPolicy{{Operator: ACLRead}})
+
+	assert.True(t, rpcAllowed(anonymous, "/types.Taskvault/GetValue", &types2.GetValueRequest{Key: "a"}))
+	assert.False(t, rpcAllowed(anonymous, "/types.Taskvault/CreateValue", &types2.CreateValueRequest{Key: "a"}))
+	assert.False(t, rpcAllowed(anonymous, "/types.Taskvault/RaftStats", nil))
+	assert.False(t, rpcAllowed(anonymous, "/types.Taskvault/RaftGetConfiguration", nil))
+	assert.True(t, rpcAllowed(operator, "/types.Taskvault/RaftStats", nil))
+	assert.True(t, rpcAllowed(operator, "/types.Taskvault/RaftGetConfiguration", nil))
+	assert.False(t, rpcAllowed(operator, "/types.Taskvault/RaftTransferLeader", nil))
+	assert.False(t, rpcAllowed(operator, "/types.Taskvault/Unknown", nil), "unlisted calls need management")
+	assert.True(t, rpcAllowed(&aclAuthorizer{management: true}, "/types.Taskvault/Unknown", nil))
+	assert.True(t, rpcAllowed(nil, "/types.Taskvault/Unknown", nil), "acls disabled")
+}
+
+func TestAgent_ResolveAgentToken(t *testing.T) {
+	a := &Agent{config: &Config{
+		ACLEnabled:       true,
+		ACLDefaultPolicy: ACLPolicyDeny,
+		ACLAgentToken:    "agent",
+	}}
+
+	authz, err := a.resolveToken("agent")
+	require.NoError(t, err)
+	assert.True(t, authz.OperatorRead())
+	assert.False(t, authz.OperatorWrite())
+	assert.False(t, authz.KeyRead("a"))
+
+	authz, err = a.resolveToken("")
+	require.NoError(t, err)
+	assert.False(t, authz.OperatorRead())
+}
+
+func TestStore_ACLTokens(t *testing.T) {
+	s := newTestStore(t)
+
+	token := &ACLToken{AccessorID: "a", SecretID: "s", Policies: []string{"p"}}
+	_, err := s.ACLBootstrap(token, 1)
+	require.NoError(t, err)
+	_, err = s.ACLBootstrap(&ACLToken{AccessorID: "b", SecretID: "t"}, 2)
+	assert.ErrorIs(t, err, ErrACLBootstrapped)
+
+	// The secret of an existing token never changes.
+	updated, err := s.ACLSetToken(&ACLToken{AccessorID: "a", SecretID: "other"}, 3)
+	require.NoError(t, err)
+	assert.Equal(t, "s", updated.SecretID)
+	assert.Equal(t, uint64(1), updated.CreateIndex)
+	assert.Equal(t, uint64(3), updated.ModifyIndex)
+
+	found, err := s.ACLTokenBySecret("s")
+	require.NoError(t, err)
+	assert.Equal(t, "a", found.AccessorID)
+
+	_, err = s.ACLSetToken(&ACLToken{AccessorID: "c", SecretID: "s"}, 4)
+	assert.Error(t, err)
+
+	// ACLs are hidden from clients.
+	pairs, err := s.GetAllValues()
+	require.NoError(t, err)
+	assert.Empty(t, pairs)
+
+	require.NoError(t, s.ACLDeleteToken("a", 5))
+	_, err = s.ACLTokenBySecret("s")
+	assert.ErrorIs(t, err, ErrACLNotFound)
+	assert.ErrorIs(t, s.ACLDeleteToken("a", 6), ErrACLNotFound)
+}
+
+func TestStore_ACLPolicies(t *testing.T) {
+	s := newTestStore(t)
+
+	_, err := s.ACLSetPolicy(&ACLPolicy{Name: "b", Agent: ACLRead}, 1)
+	require.NoError(t, err)
+	_, err = s.ACLSetPolicy(&ACLPolicy{Name: "a", Operator: ACLWrite}, 2)
+	require.NoError(t, err)
+
+	policies, err := s.ACLListPolicies()
+	require.NoError(t, err)
+	require.Len(t, policies, 2)
+	assert.Equal(t, "a", policies[0].Name)
+	assert.Equal(t, "b", policies[1].Name)
+
+	require.NoError(t, s.ACLDeletePolicy("a", 3))
+	_, err = s.ACLGetPolicy("a")
+	assert.ErrorIs(t, err, ErrACLNotFound)
+	assert.ErrorIs(t, s.ACLDeletePolicy("a", 4), ErrACLNotFound)
+
+	index, err := s.AppliedIndex()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), index)
+}
@@ -1,6 +1,8 @@
 package taskvault
 
 import (
+	"context"
+	"crypto/tls"
 	"encoding/base64"
 	"errors"
 	"fmt"
@@ -8,10 +10,12 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/danluki/taskvault/pkg/types"
 	"github.com/hashicorp/memberlist"
 	"github.com/hashicorp/raft"
 	raftboltdb "github.com/hashicorp/raft-boltdb"
@@ -19,17 +23,27 @@ import (
 	"github.com/soheilhy/cmux"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
 )
 
 const (
 	raftTimeout      = 30 * time.Second
 	raftLogCacheSize = 512
 	raftMultiplier   = 1
+
+	// refreshChSize buffers the member events waiting for the leader loop,
+	// events beyond it are picked up by the periodic refresh.
+	refreshChSize = 256
 )
 
+// leaveRaftPollInterval is how often a leaving server checks whether it was
+// removed from the Raft configuration.
+const leaveRaftPollInterval = 100 * time.Millisecond
+
 var (
 	ErrLeaderNotFound   = errors.New("no member leader found")
 	ErrNoSuitableServer = errors.New("no suitable server found")
+	errLeaveTimeout     = errors.New("timed out")
 )
 
 type Node = serf.Member
@@ -38,8 +52,9 @@ type Agent struct {
 	Store  SyncraStorage
 	config *Config
 
-	serfEventer chan serf.Event
-	shutdowner  chan struct{}
+	serfEventer  chan serf.Event
+	shutdowner   chan struct{}
+	shutdownOnce sync.Once
 
 	raftTransport *raft.NetworkTransport
 	raft          *raft.Raft
@@ -54,18 +69,30 @@ type Agent struct {
 	leaderCh      <-chan bool
 	serverLookup  *ServerLookup
 	listener      net.Listener
+	watches       *watchHub
+	autopilot     *autopilot
+	tls           *tlsConfigurator
+
+	// readyForReads is set while this node is leader and has applied the
+	// entries committed before it took over.
+	readyForReads atomic.Bool
 
 	logger *zap.SugaredLogger
 
 	raftInmemStore *raft.InmemStore
+	snapshots      raft.SnapshotStore
 }
 
 func NewAgent(config *Config) *Agent {
 	agent := &Agent{
 		config:       config,
 		retryJoinCh:  make(chan error),
+		shutdowner:   make(chan struct{}),
+		refreshCh:    make(chan serf.Member, refreshChSize),
 		serverLookup: NewServerLookup(),
+		watches:      newWatchHub(),
 	}
+	agent.autopilot = newAutopilot(agent)
 
 	return agent
 }
@@ -80,6 +107,30 @@ func (a *Agent) Start() error {
 		}
 	}
 
+	if a.config.Replica && (a.config.Bootstrap || a.config.BootstrapExpect != 0) {
+		return errors.New("agent: a replica can not bootstrap the cluster")
+	}
+
+	a.tls, err = newTLSConfigurator(a.config)
+	if err != nil {
+		return fmt.Errorf("agent: %w", err)
+	}
+	if a.tls != nil && a.config.CertFile == "" {
+		return errors.New("agent: cert-file is required to serve TLS")
+	}
+	if a.config.HTTPS && a.tls == nil {
+		return errors.New("agent: https requires cert-file, key-file and ca-file")
+	}
+	if _, err := corsConfig(a.config.CORSAllowedOrigins); err != nil {
+		return fmt.Errorf("agent: invalid cors-allowed-origins: %w", err)
+	}
+	if a.config.ACLDefaultPolicy != ACLPolicyAllow && a.config.ACLDefaultPolicy != ACLPolicyDeny {
+		return fmt.Errorf("agent: unknown acl-default-policy %q", a.config.ACLDefaultPolicy)
+	}
+	if a.config.ACLEnabled && a.config.ACLDefaultPolicy == ACLPolicyDeny && a.config.ACLAgentToken == "" {
+		a.logger.Warn("agent: acl-agent-token is not set, servers can not bootstrap or check each other's health")
+	}
+
 	a.serf, err = a.setupSerf()
 	if err != nil {
 		return fmt.Errorf("agent: Can not setup serf, %s", err)
@@ -110,11 +161,16 @@ func (a *Agent) Start() error {
 	a.StartServer()
 
 	if a.GRPCClient == nil {
-		a.GRPCClient = NewGRPCClient(nil, a, a.logger)
+		var dialOpt grpc.DialOption
+		if a.tls != nil {
+			dialOpt = grpc.WithTransportCredentials(a.tls.TransportCredentials())
+		}
+		a.GRPCClient = NewGRPCClient(dialOpt, a, a.logger)
 	}
 
 	tags := a.serf.LocalMember().Tags
 	tags["rpc_addr"] = a.advertiseRPCAddr()
+	tags["http_addr"] = a.advertiseHTTPAddr()
 	tags["port"] = strconv.Itoa(a.config.AdvertiseRPCPort)
 	if err := a.serf.SetTags(tags); err != nil {
 		return fmt.Errorf("agent: Error setting tags: %w", err)
@@ -125,6 +181,20 @@ func (a *Agent) Start() error {
 	return nil
 }
 
+// Reload reloads the parts of the configuration that can change at runtime,
+// currently the TLS certificates.
+func (a *Agent) Reload() error {
+	if a.tls == nil {
+		return nil
+	}
+	if err := a.tls.Reload(); err != nil {
+		return err
+	}
+
+	a.logger.Info("agent: reloaded TLS certificates")
+	return nil
+}
+
 func (a *Agent) RetryJoinCh() <-chan error {
 	return a.retryJoinCh
 }
@@ -133,26 +203,182 @@ func (a *Agent) JoinLAN(addrs []string) (int, error) {
 	return a.serf.Join(addrs, true)
 }
 
+// Stop leaves the cluster gracefully and shuts the agent down.
 func (a *Agent) Stop() error {
 	a.logger.Info("agent: Called member stop, now stopping")
 
-	_ = a.raft.Shutdown()
+	if err := a.Leave(); err != nil {
+		a.logger.With(zap.Error(err)).Warn("agent: failed to leave the cluster")
+	}
 
-	if err := a.Store.Shutdown(); err != nil {
-		return err
+	return a.Shutdown()
+}
+
+// Leave hands leadership over, tells the cluster this server is leaving and
+// waits for the leader to remove it from the Raft configuration. Each step
+// is bounded by its timeout, only a failed serf leave is reported.
+func (a *Agent) Leave() error {
+	a.logger.Info("agent: leaving the cluster")
+
+	// Hand leadership over first so the cluster does not have to wait for
+	// an election timeout to notice the leader is gone.
+	if a.IsLeader() {
+		err := withTimeout(a.config.LeaveTransferTimeout, func() error {
+			_, _, err := a.transferLeadership("")
+			return err
+		})
+		if err != nil && !errors.Is(err, ErrNoTransferTarget) {
+			a.logger.With(zap.Error(err)).Warn("agent: failed to transfer leadership")
+		}
+	}
+	// A leader that could not hand over removes itself, nobody else would.
+	if a.IsLeader() {
+		err := a.removeRaftPeerByID(raft.ServerID(a.config.NodeName))
+		if err != nil && !errors.Is(err, ErrLastVoter) {
+			a.logger.With(zap.Error(err)).Warn("agent: failed to remove itself from raft")
+		}
 	}
 
 	if err := a.serf.Leave(); err != nil {
 		return err
 	}
 
+	if err := a.waitRaftRemoval(a.config.LeaveRaftTimeout); err != nil {
+		a.logger.With(zap.Error(err)).Warn("agent: not removed from raft")
+	}
+
+	return nil
+}
+
+// waitRaftRemoval waits until this server is no longer part of the Raft
+// configuration. A server that is the last one of the configuration is
+// never removed and does not wait.
+func (a *Agent) waitRaftRemoval(timeout time.Duration) error {
+	self := a.config.NodeName
+	deadline := time.Now().Add(timeout)
+
+	for {
+		ids, err := a.raftServerIDs()
+		if err == nil && (!slices.Contains(ids, self) || len(ids) == 1) {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			if err != nil {
+				return err
+			}
+			return errLeaveTimeout
+		}
+		time.Sleep(leaveRaftPollInterval)
+	}
+}
+
+// raftServerIDs returns the IDs of the Raft configuration as the leader
+// knows it, the leader stops replicating to a server once it removed it so
+// the server would never see its own removal. The local configuration is
+// used while there is no leader to ask.
+func (a *Agent) raftServerIDs() ([]string, error) {
+	var ids []string
+
+	addr, _ := a.raft.LeaderWithID()
+	if addr != "" && !a.IsLeader() {
+		res, err := a.serverClient().RaftGetConfiguration(string(addr))
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range res.Servers {
+			ids = append(ids, s.Id)
+		}
+		return ids, nil
+	}
+
+	future := a.raft.GetConfiguration()
+	if err := future.Error(); err != nil {
+		return nil, err
+	}
+	for _, s := range future.Configuration().Servers {
+		ids = append(ids, string(s.ID))
+	}
+
+	return ids, nil
+}
+
+// Shutdown drains the in-flight HTTP and gRPC requests and stops every
+// part of the agent. It does not leave the cluster, the other members see
+// this server as failed. Calling it again does nothing.
+func (a *Agent) Shutdown() error {
+	var err error
+	a.shutdownOnce.Do(func() {
+		err = a.shutdown()
+		close(a.shutdowner)
+	})
+
+	return err
+}
+
+func (a *Agent) shutdown() error {
+	a.logger.Info("agent: shutting down")
+
+	ctx, cancel := context.WithTimeout(context.Background(), a.config.LeaveDrainTimeout)
+	defer cancel()
+	if a.HTTPTransport != nil {
+		if err := a.HTTPTransport.Shutdown(ctx); err != nil {
+			a.logger.With(zap.Error(err)).Warn("agent: HTTP requests cut off")
+		}
+	}
+
+	if a.raft != nil {
+		if err := a.raft.Shutdown().Error(); err != nil {
+			a.logger.With(zap.Error(err)).Warn("agent: failed to shut down raft")
+		}
+	}
+	// Closes the listener shared by Raft and gRPC, the gRPC calls already
+	// accepted are drained below.
+	if a.raftTransport != nil {
+		_ = a.raftTransport.Close()
+	}
+
+	if a.GRPCServer != nil {
+		a.GRPCServer.Shutdown(ctx)
+	}
+
+	if a.GRPCClient != nil {
+		a.GRPCClient.Shutdown()
+	}
+
 	if err := a.serf.Shutdown(); err != nil {
 		return err
 	}
 
+	if a.Store != nil {
+		if err := withTimeout(a.config.LeaveStoreTimeout, a.Store.Shutdown); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// ShutdownCh is closed once the agent has shut down.
+func (a *Agent) ShutdownCh() <-chan struct{} {
+	return a.shutdowner
+}
+
+// withTimeout runs fn and gives up waiting for it after timeout.
+func withTimeout(timeout time.Duration, fn func() error) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- fn()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-time.After(timeout):
+		return errLeaveTimeout
+	}
+}
+
 func (a *Agent) setupRaft() error {
 	if a.config.BootstrapExpect == 1 {
 		a.config.Bootstrap = true
@@ -189,7 +415,7 @@ func (a *Agent) setupRaft() error {
 		store := raft.NewInmemStore()
 		stableStore = store
 		logStore = store
-		snapshots = raft.NewDiscardSnapshotStore()
+		snapshots = raft.NewInmemSnapshotStore()
 		a.raftInmemStore = store
 	} else {
 		var err error
@@ -246,7 +472,22 @@ func (a *Agent) setupRaft() error {
 		}
 	}
 
-	fsm := newFSM(a.Store, a.logger)
+	a.snapshots = snapshots
+
+	fsm := newFSM(a.Store, a.watches, a.logger)
+	if !a.config.DevMode {
+		persisted, err := a.persistedIndex(logStore, snapshots)
+		if err != nil {
+			return err
+		}
+		if persisted > 0 {
+			// The store is at least as recent as the latest snapshot, only
+			// the log entries it is missing have to be applied.
+			config.NoSnapshotRestoreOnStart = true
+			fsm.skipPersisted(persisted)
+		}
+	}
+
 	rft, err := raft.NewRaft(
 		config, fsm, logStore, stableStore, snapshots, transport,
 	)
@@ -259,6 +500,80 @@ func (a *Agent) setupRaft() error {
 	return nil
 }
 
+// openStore opens the state store selected by the configuration. Dev mode
+// always keeps its state in memory.
+func (a *Agent) openStore() (SyncraStorage, error) {
+	backend := a.config.StoreBackend
+	if a.config.DevMode {
+		backend = StoreBackendMemory
+	}
+
+	switch backend {
+	case StoreBackendMemory:
+		return NewStore(a.logger)
+	case StoreBackendFile, "":
+		dir := filepath.Join(a.config.DataDir, "store")
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+		return NewFileStore(
+			filepath.Join(dir, "state.db"), a.config.StoreSync, a.logger,
+		)
+	}
+
+	return nil, fmt.Errorf("agent: unknown store backend %q", backend)
+}
+
+// persistedIndex works out where the state store stands relative to the
+// Raft data. It returns the index up to which the store already holds the
+// log, or 0 when the store has to be rebuilt from the latest snapshot and
+// the log, as Raft does for a fresh store.
+func (a *Agent) persistedIndex(
+	logs raft.LogStore, snapshots raft.SnapshotStore,
+) (uint64, error) {
+	applied, err := a.Store.AppliedIndex()
+	if err != nil || applied == 0 {
+		return 0, err
+	}
+
+	var snapshotIndex uint64
+	snaps, err := snapshots.List()
+	if err != nil {
+		return 0, err
+	}
+	if len(snaps) > 0 {
+		snapshotIndex = snaps[0].Index
+	}
+
+	lastIndex, err := logs.LastIndex()
+	if err != nil {
+		return 0, err
+	}
+
+	switch {
+	case applied < snapshotIndex:
+		a.logger.Infof(
+			"agent: state store at index %d is behind snapshot %d, restoring it",
+			applied, snapshotIndex,
+		)
+		return 0, nil
+	case applied > max(snapshotIndex, lastIndex):
+		return 0, fmt.Errorf(
+			"agent: state store at index %d is ahead of the raft log at index %d, "+
+				"remove %s to rebuild it",
+			applied, max(snapshotIndex, lastIndex),
+			filepath.Join(a.config.DataDir, "store"),
+		)
+	}
+
+	a.logger.Infof(
+		"agent: state store is at index %d, replaying the raft log up to %d",
+		applied, lastIndex,
+	)
+
+	return applied, nil
+}
+
 func (a *Agent) setupSerf() (*serf.Serf, error) {
 	bindIP, bindPort, err := a.config.AddrParts(a.config.BindAddr)
 	if err != nil {
@@ -289,6 +604,9 @@ func (a *Agent) setupSerf() (*serf.Serf, error) {
 	if a.config.BootstrapExpect != 0 {
 		serfConfig.Tags["expect"] = fmt.Sprintf("%d", a.config.BootstrapExpect)
 	}
+	if a.config.Replica {
+		serfConfig.Tags[roleTag] = roleReplica
+	}
 
 	switch a.config.Profile {
 	case "lan":
@@ -311,6 +629,7 @@ func (a *Agent) setupSerf() (*serf.Serf, error) {
 	serfConfig.QuiescentPeriod = time.Second
 	serfConfig.UserCoalescePeriod = 3 * time.Second
 	serfConfig.UserQuiescentPeriod = time.Second
+	serfConfig.BroadcastTimeout = a.config.LeaveBroadcastTimeout
 	serfConfig.ReconnectTimeout, err = time.ParseDuration(a.config.SerfReconnectTimeout)
 
 	if err != nil {
@@ -341,7 +660,7 @@ func (a *Agent) setupSerf() (*serf.Serf, error) {
 func (a *Agent) StartServer() {
 	var err error
 	if a.Store == nil {
-		a.Store, err = NewStore(a.logger)
+		a.Store, err = a.openStore()
 		if err != nil {
 			panic(err)
 		}
@@ -350,10 +669,18 @@ func (a *Agent) StartServer() {
 	a.HTTPTransport = NewTransport(a, a.logger)
 	a.HTTPTransport.ServeHTTP()
 
-	tcpm := cmux.New(a.listener)
-	var grpcl, raftl net.Listener
+	// With TLS the whole port is encrypted and cmux tells Raft and gRPC
+	// apart on the decrypted stream.
+	listener := a.listener
+	if a.tls != nil {
+		listener = tls.NewListener(listener, a.tls.IncomingConfig())
+		a.raftLayer = NewTLSRaftLayer(a.tls, a.logger)
+	} else {
+		a.raftLayer = NewRaftLayer(a.logger)
+	}
 
-	a.raftLayer = NewRaftLayer(a.logger)
+	tcpm := cmux.New(listener)
+	var grpcl, raftl net.Listener
 
 	grpcl = tcpm.MatchWithWriters(
 		cmux.HTTP2MatchHeaderFieldSendSettings(
@@ -375,7 +702,8 @@ func (a *Agent) StartServer() {
 	}
 
 	go func() {
-		if err := tcpm.Serve(); err != nil {
+		// The listener is closed when the agent shuts down.
+		if err := tcpm.Serve(); err != nil && !errors.Is(err, net.ErrClosed) {
 			a.logger.Fatal(err)
 		}
 	}()
@@ -393,6 +721,21 @@ func (a *Agent) leaderMember() (*serf.Member, error) {
 	return nil, ErrLeaderNotFound
 }
 
+// leaderHTTPAddr returns the address of the leader's HTTP API.
+func (a *Agent) leaderHTTPAddr() (string, error) {
+	member, err := a.leaderMember()
+	if err != nil {
+		return "", err
+	}
+
+	addr, ok := member.Tags["http_addr"]
+	if !ok {
+		return "", fmt.Errorf("leader %s does not advertise its HTTP address", member.Name)
+	}
+
+	return addr, nil
+}
+
 func (a *Agent) IsLeader() bool {
 	return a.raft.State() == raft.Leader
 }
@@ -468,21 +811,42 @@ func (a *Agent) advertiseRPCAddr() string {
 	)
 }
 
+// advertiseHTTPAddr returns the address other members reach the HTTP API
+// at, the advertised IP is used when the API listens on all interfaces.
+func (a *Agent) advertiseHTTPAddr() string {
+	host, port, err := net.SplitHostPort(a.config.HTTPAddr)
+	if err != nil {
+		return a.config.HTTPAddr
+	}
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = a.serf.LocalMember().Addr.String()
+	}
+
+	return net.JoinHostPort(host, port)
+}
+
 func (a *Agent) bindRPCAddr() string {
 	bindIP, _, _ := a.config.AddrParts(a.config.BindAddr)
 	return net.JoinHostPort(bindIP, strconv.Itoa(a.config.RPCPort))
 }
 
-func (a *Agent) applySetPair(pair *types.Pair) error {
-	cmd, err := Encode(AddPairType, pair)
+// raftApply submits a command to Raft and returns the FSM response. Errors
+// returned by the FSM are reported as errors rather than as the response.
+func (a *Agent) raftApply(t MessageType, msg any) (interface{}, error) {
+	cmd, err := Encode(t, msg)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	af := a.raft.Apply(cmd, raftTimeout)
 	if err := af.Error(); err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	res := af.Response()
+	if err, ok := res.(error); ok {
+		return nil, err
+	}
+
+	return res, nil
 }
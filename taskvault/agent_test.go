@@ -98,6 +98,8 @@ func Test_bindRPCAddr(t *testing.T) {
 	bindRPCAddr := a.bindRPCAddr()
 	exRPCAddr := a1Addr + ":6868"
 	assert.Equal(t, exRPCAddr, bindRPCAddr)
+
+	_ = a.Stop()
 }
 
 func TestAgentConfig(t *testing.T) {
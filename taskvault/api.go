@@ -1,29 +1,57 @@
 package taskvault
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
 	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/danluki/taskvault/pkg/types"
 	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/raft"
 	"go.uber.org/zap"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const (
 	pretty        = "pretty"
 	apiPathPrefix = "v1"
+	indexHeader   = "X-Taskvault-Index"
+
+	knownLeaderHeader = "X-Taskvault-KnownLeader"
+	lastContactHeader = "X-Taskvault-LastContact"
+	// forwardedHeader marks requests proxied to the leader.
+	forwardedHeader = "X-Taskvault-Forwarded"
+
+	// octetStream is the content type of raw values.
+	octetStream = "application/octet-stream"
+
+	defaultQueryWait = 5 * time.Minute
+	maxQueryWait     = 10 * time.Minute
 )
 
 type Transport interface {
 	ServeHTTP()
+	Shutdown(ctx context.Context) error
 }
 
 type HTTPTransport struct {
 	Engine *gin.Engine
+	server *http.Server
 
 	agent  *Agent
 	logger *zap.SugaredLogger
@@ -41,28 +69,77 @@ func (h *HTTPTransport) ServeHTTP() {
 
 	rootPath := h.Engine.Group("/")
 
-	config := cors.DefaultConfig()
-	config.AllowAllOrigins = true
-	config.AllowMethods = []string{"*"}
-	config.AllowHeaders = []string{"*"}
-	config.ExposeHeaders = []string{"*"}
-
-	rootPath.Use(cors.New(config))
+	// Validated when the agent starts.
+	if config, _ := corsConfig(h.agent.config.CORSAllowedOrigins); config != nil {
+		rootPath.Use(cors.New(*config))
+	}
 
-	h.APIRoutes(rootPath)
+	h.APIRoutes(rootPath, h.aclMiddleware)
 	if h.agent.config.UI {
 		h.UI(rootPath)
 	}
 
-	h.logger.Info("api: Running HTTP server", zap.String("address", h.agent.config.HTTPAddr))
+	h.logger.Info("api: Running HTTP server",
+		zap.String("address", h.agent.config.HTTPAddr),
+		zap.Bool("https", h.agent.config.HTTPS),
+	)
 
+	h.server = &http.Server{
+		Addr:    h.agent.config.HTTPAddr,
+		Handler: h.Engine,
+	}
 	go func() {
-		if err := h.Engine.Run(h.agent.config.HTTPAddr); err != nil {
+		var err error
+		if h.agent.config.HTTPS {
+			h.server.TLSConfig = h.agent.tls.HTTPConfig(h.agent.config.HTTPVerifyClients)
+			err = h.server.ListenAndServeTLS("", "")
+		} else {
+			err = h.server.ListenAndServe()
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			panic(err)
 		}
 	}()
 }
 
+// Shutdown stops accepting requests and waits for the in-flight ones until
+// ctx is done, the remaining connections are closed then.
+func (h *HTTPTransport) Shutdown(ctx context.Context) error {
+	if h.server == nil {
+		return nil
+	}
+
+	err := h.server.Shutdown(ctx)
+	if err != nil {
+		_ = h.server.Close()
+	}
+	return err
+}
+
+// corsConfig returns the CORS config allowing origins, nil when no origin
+// is allowed.
+func corsConfig(origins []string) (*cors.Config, error) {
+	if len(origins) == 0 {
+		return nil, nil
+	}
+
+	config := cors.DefaultConfig()
+	if slices.Contains(origins, "*") {
+		config.AllowAllOrigins = true
+	} else {
+		config.AllowOrigins = origins
+	}
+	config.AllowMethods = []string{"*"}
+	config.AllowHeaders = []string{"*"}
+	config.ExposeHeaders = []string{"*"}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
 func (h *HTTPTransport) APIRoutes(
 	r *gin.RouterGroup, middleware ...gin.HandlerFunc,
 ) {
@@ -80,9 +157,9 @@ func (h *HTTPTransport) APIRoutes(
 		r.GET("/metrics", gin.WrapH(promhttp.Handler()))
 	}
 
-	r.GET("/v1", h.indexHandler)
 	v1 := r.Group("/v1")
 	v1.Use(middleware...)
+	v1.GET("", h.indexHandler)
 	v1.GET("/", h.indexHandler)
 	v1.GET("/members", h.membersHandler)
 	v1.GET("/leader", h.leaderHandler)
@@ -91,10 +168,52 @@ func (h *HTTPTransport) APIRoutes(
 
 	pairs := v1.Group("/storage")
 	pairs.GET("", h.pairsHandler)
-	pairs.GET("/:key", h.pairGetHandler)
+	pairs.GET("/*key", h.pairGetHandler)
 	pairs.POST("", h.pairPostHandler)
-	pairs.DELETE("/:key", h.pairDeleteHandler)
-	pairs.PATCH("/", h.pairDeleteHandler)
+	pairs.PUT("/*key", h.pairPutHandler)
+	pairs.DELETE("/*key", h.pairDeleteHandler)
+
+	v1.POST("/txn", h.txnHandler)
+
+	v1.GET("/snapshot", h.snapshotGetHandler)
+	v1.PUT("/snapshot", h.snapshotPutHandler)
+
+	operator := v1.Group("/operator")
+	operator.POST("/raft/transfer-leader", h.transferLeaderHandler)
+	operator.GET("/autopilot/health", h.autopilotHealthHandler)
+
+	v1.GET("/sessions", h.sessionListHandler)
+	v1.POST("/session", h.sessionCreateHandler)
+	v1.GET("/session/:id", h.sessionGetHandler)
+	v1.PUT("/session/:id/renew", h.sessionRenewHandler)
+	v1.DELETE("/session/:id", h.sessionDestroyHandler)
+
+	v1.GET("/queues", h.queueListHandler)
+	queues := v1.Group("/queues/:name")
+	queues.GET("/tasks", h.queueTasksHandler)
+	queues.POST("/tasks", h.queueEnqueueHandler)
+	queues.POST("/claim", h.queueClaimHandler)
+	queues.POST("/tasks/:id/ack", h.queueAckHandler)
+	queues.POST("/tasks/:id/nack", h.queueNackHandler)
+	queues.DELETE("/tasks/:id", h.queueDeleteHandler)
+
+	v1.GET("/jobs", h.jobListHandler)
+	v1.GET("/jobs/:name", h.jobGetHandler)
+	v1.PUT("/jobs/:name", h.jobSetHandler)
+	v1.DELETE("/jobs/:name", h.jobDeleteHandler)
+
+	acl := v1.Group("/acl")
+	acl.Use(h.aclEnabledMiddleware)
+	acl.POST("/bootstrap", h.aclBootstrapHandler)
+	acl.GET("/tokens", h.aclTokenListHandler)
+	acl.POST("/token", h.aclTokenCreateHandler)
+	acl.GET("/token/:accessor", h.aclTokenGetHandler)
+	acl.PUT("/token/:accessor", h.aclTokenUpdateHandler)
+	acl.DELETE("/token/:accessor", h.aclTokenDeleteHandler)
+	acl.GET("/policies", h.aclPolicyListHandler)
+	acl.GET("/policy/:name", h.aclPolicyGetHandler)
+	acl.PUT("/policy/:name", h.aclPolicySetHandler)
+	acl.DELETE("/policy/:name", h.aclPolicyDeleteHandler)
 }
 
 func renderJSON(c *gin.Context, status int, v interface{}) {
@@ -109,7 +228,7 @@ func (h *HTTPTransport) membersHandler(c *gin.Context) {
 	mems := []*types.Member{}
 	for _, m := range h.agent.serf.Members() {
 		id, _ := uuid.GenerateUUID()
-		mid := &types.Member{m, id, m.Status.String()}
+		mid := &types.Member{Member: m, Id: id, StatusText: m.Status.String()}
 		mems = append(mems, mid)
 	}
 	c.Header("X-Total-Count", strconv.Itoa(len(mems)))
@@ -137,11 +256,16 @@ func (h *HTTPTransport) isLeaderHandler(c *gin.Context) {
 	renderJSON(c, http.StatusNotFound, "follower")
 }
 
+// leaveHandler leaves the cluster gracefully and answers before the agent
+// shuts down, the shutdown waits for this request to finish.
 func (h *HTTPTransport) leaveHandler(c *gin.Context) {
-	if err := h.agent.Stop(); err != nil {
+	if err := h.agent.Leave(); err != nil {
 		_ = c.AbortWithError(http.StatusInternalServerError, err)
+		return
 	}
 	renderJSON(c, http.StatusOK, h.agent.serf.Memberlist())
+
+	go h.agent.Shutdown()
 }
 
 func (h *HTTPTransport) indexHandler(c *gin.Context) {
@@ -160,51 +284,241 @@ func (h *HTTPTransport) indexHandler(c *gin.Context) {
 }
 
 func (h *HTTPTransport) pairsHandler(c *gin.Context) {
-	pairs, err := h.agent.Store.GetAllValues()
-	if err != nil {
-		return
+	opts := ListOptions{
+		Prefix:    c.Query("prefix"),
+		StartKey:  c.Query("start_key"),
+		EndKey:    c.Query("end_key"),
+		Separator: c.Query("separator"),
+	}
+	_, opts.KeysOnly = c.GetQuery("keys_only")
+	if opts.Separator != "" {
+		opts.KeysOnly = true
 	}
 
 	start, ok := c.GetQuery("_start")
 	if !ok {
 		start = "0"
 	}
-	s, _ := strconv.Atoi(start)
+	opts.Offset, _ = strconv.Atoi(start)
 
-	end, ok := c.GetQuery("_end")
-	e := 0
-	if !ok {
-		e = len(pairs)
-	} else {
-		e, _ = strconv.Atoi(end)
-		if e > len(pairs) {
-			e = len(pairs)
+	if end, ok := c.GetQuery("_end"); ok {
+		e, _ := strconv.Atoi(end)
+		opts.Limit = e - opts.Offset
+		if opts.Limit <= 0 {
+			// Empty page, only the total count is reported.
+			opts.Offset, opts.Limit = math.MaxInt, 0
+		}
+	}
+
+	minIndex, wait, err := parseBlocking(c)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+	mode, err := parseConsistency(c.Query("consistency"))
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	var (
+		pairs []Pair
+		total int
+		meta  QueryMeta
+	)
+	index, err := h.blockingQuery(c, opts.Prefix, true, minIndex, wait, func() (uint64, error) {
+		var err error
+		pairs, total, meta, err = h.agent.listValues(c.Request.Context(), opts, mode)
+		if err != nil {
+			return 0, err
 		}
+
+		// Deletes and changes outside the page do not show in the pairs,
+		// the index is the last change anywhere under the prefix.
+		index, ok := h.agent.watches.lastIndex(opts.Prefix, true)
+		if !ok {
+			index = h.agent.raft.AppliedIndex()
+		}
+		for _, p := range pairs {
+			index = max(index, p.ModifyIndex)
+		}
+		return index, nil
+	})
+	if err != nil {
+		h.renderReadError(c, err)
+		return
 	}
 
-	c.Header("X-Total-Count", strconv.Itoa(len(pairs)))
-	renderJSON(c, http.StatusOK, pairs[s:e])
+	setIndexHeader(c, index)
+	setQueryMetaHeaders(c, meta)
+	c.Header("X-Total-Count", strconv.Itoa(total))
+	if opts.KeysOnly {
+		keys := make([]string, len(pairs))
+		for i, p := range pairs {
+			keys[i] = p.Key
+		}
+		renderJSON(c, http.StatusOK, keys)
+		return
+	}
+
+	if pairs == nil {
+		pairs = []Pair{}
+	}
+	if !wantMeta(c) {
+		for i := range pairs {
+			pairs[i].Meta = nil
+		}
+	}
+	renderJSON(c, http.StatusOK, pairs)
 }
 
+// pairGetHandler returns a key, with its metadata when asked with ?meta=true.
+// With ?raw only the value is returned, served with the content type of the
+// key.
 func (h *HTTPTransport) pairGetHandler(c *gin.Context) {
-	pairName := c.Param("key")
+	pairName := keyParam(c)
 
-	pair, err := h.agent.Store.GetValue(pairName)
+	minIndex, wait, err := parseBlocking(c)
 	if err != nil {
-		h.logger.Error(err)
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+	mode, err := parseConsistency(c.Query("consistency"))
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	var (
+		pair *Pair
+		meta QueryMeta
+	)
+	index, err := h.blockingQuery(c, pairName, false, minIndex, wait, func() (uint64, error) {
+		var err error
+		pair, meta, err = h.agent.getValue(c.Request.Context(), pairName, mode)
+		if err != nil {
+			return 0, err
+		}
+		return pair.ModifyIndex, nil
+	})
+	if errors.Is(err, ErrKeyNotFound) {
+		// Missing keys carry no metadata from the node that served the read.
+		meta = h.agent.queryMeta()
+	} else if err != nil {
+		h.renderReadError(c, err)
+		return
+	}
+
+	setIndexHeader(c, index)
+	setQueryMetaHeaders(c, meta)
+	if pair == nil {
 		c.Status(http.StatusNotFound)
 		return
 	}
 
+	if _, raw := c.GetQuery("raw"); raw {
+		contentType := octetStream
+		if pair.Meta != nil && pair.Meta.ContentType != "" {
+			contentType = pair.Meta.ContentType
+		}
+		c.Data(http.StatusOK, contentType, []byte(pair.Value))
+		return
+	}
+	if !wantMeta(c) {
+		pair.Meta = nil
+	}
 	renderJSON(c, http.StatusOK, pair)
 }
 
+// parseBlocking reads the ?index=N&wait=D parameters of a blocking query. An
+// index of 0 means the query does not block.
+func parseBlocking(c *gin.Context) (uint64, time.Duration, error) {
+	var index uint64
+	if v, ok := c.GetQuery("index"); ok {
+		var err error
+		index, err = strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid index %q: %w", v, err)
+		}
+	}
+
+	wait := defaultQueryWait
+	if v, ok := c.GetQuery("wait"); ok {
+		var err error
+		wait, err = time.ParseDuration(v)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid wait %q: %w", v, err)
+		}
+	}
+
+	return index, min(wait, maxQueryWait), nil
+}
+
+// blockingQuery runs read and, when minIndex is set, holds the request until
+// a change to key (or to keys under it when prefix is set) is applied past
+// minIndex or wait elapses. read returns the index of the data it read, the
+// applied index is reported instead when there is none so that clients never
+// loop on index 0.
+func (h *HTTPTransport) blockingQuery(
+	c *gin.Context,
+	key string,
+	prefix bool,
+	minIndex uint64,
+	wait time.Duration,
+	read func() (uint64, error),
+) (uint64, error) {
+	run := func() (uint64, error) {
+		index, err := read()
+		if index == 0 {
+			index = h.agent.raft.AppliedIndex()
+		}
+		return index, err
+	}
+
+	if minIndex == 0 {
+		return run()
+	}
+
+	w := h.agent.watches.Watch(key, prefix)
+	defer h.agent.watches.Stop(w)
+
+	index, err := run()
+	if index > minIndex {
+		return index, err
+	}
+
+	timeout := time.NewTimer(wait)
+	defer timeout.Stop()
+
+	for {
+		select {
+		case changed := <-w.ch:
+			if changed != 0 && changed <= minIndex {
+				continue
+			}
+			index, err := run()
+			return max(index, changed), err
+		case <-timeout.C:
+			return index, err
+		case <-c.Request.Context().Done():
+			return index, err
+		}
+	}
+}
+
 func (h *HTTPTransport) pairDeleteHandler(c *gin.Context) {
-	keyName := c.Param("key")
+	cas, err := parseCAS(c)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
 
-	err := h.agent.GRPCClient.DeleteValue(keyName)
+	err = h.client(c).DeleteValue(&types.DeleteValueRequest{
+		Key: keyParam(c),
+		Cas: cas,
+	})
 	if err != nil {
-		_ = c.AbortWithError(http.StatusNotFound, err)
+		h.renderWriteError(c, err)
 		return
 	}
 
@@ -219,12 +533,504 @@ func (h *HTTPTransport) pairPostHandler(c *gin.Context) {
 		return
 	}
 
-	if _, err := h.agent.GRPCClient.CreateValue(
-		pair.Key, pair.Value,
-	); err != nil {
+	cas, err := parseCAS(c)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	created, err := h.client(c).CreateValue(&types.CreateValueRequest{
+		Key:   pair.Key,
+		Value: []byte(pair.Value),
+		Cas:   cas,
+		Ttl:   pair.TTL,
+		Meta:  pair.Meta.proto(),
+	})
+	if err != nil {
+		h.renderWriteError(c, err)
+		return
+	}
+
+	setIndexHeader(c, created.ModifyIndex)
+	renderJSON(c, http.StatusCreated, created)
+}
+
+// pairPutHandler updates a key, which must exist unless ?create is given.
+// With ?acquire=<session> or ?release=<session> the write also takes or gives
+// up the lock of the key. With ?incr or ?decr the key is a counter, see
+// pairIncrHandler. An application/octet-stream body is stored as is, its TTL
+// is then given as ?ttl=. The Meta of a JSON body replaces the flags, content
+// type and labels of the key, writes without keep them.
+func (h *HTTPTransport) pairPutHandler(c *gin.Context) {
+	_, incr := c.GetQuery("incr")
+	_, decr := c.GetQuery("decr")
+	if incr || decr {
+		h.pairIncrHandler(c)
+		return
+	}
+
+	pair := &Pair{}
+	raw := c.ContentType() == octetStream
+	if raw {
+		if err := h.bindRawPair(c, pair); err != nil {
+			h.renderWriteError(c, err)
+			return
+		}
+	} else if err := c.ShouldBindJSON(pair); err != nil {
+		h.logger.Error(err)
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	cas, err := parseCAS(c)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	_, create := c.GetQuery("create")
+
+	var updated *Pair
+	if acquire := c.Query("acquire"); acquire != "" || create {
+		// Locks are usually taken on keys that do not exist yet, acquiring
+		// creates the key. Raw values have no POST to create them with and
+		// use ?create.
+		updated, err = h.client(c).CreateValue(&types.CreateValueRequest{
+			Key:     keyParam(c),
+			Value:   []byte(pair.Value),
+			Cas:     cas,
+			Ttl:     pair.TTL,
+			Acquire: acquire,
+			Release: c.Query("release"),
+			Meta:    pair.Meta.proto(),
+		})
+	} else {
+		updated, err = h.client(c).UpdateValue(&types.UpdateValueRequest{
+			Key:     keyParam(c),
+			Value:   []byte(pair.Value),
+			Cas:     cas,
+			Ttl:     pair.TTL,
+			Release: c.Query("release"),
+			Meta:    pair.Meta.proto(),
+		})
+	}
+	if err != nil {
+		h.renderWriteError(c, err)
+		return
+	}
+
+	setIndexHeader(c, updated.ModifyIndex)
+	renderJSON(c, http.StatusOK, updated)
+}
+
+// bindRawPair reads the value of pair from a raw body and its TTL from the
+// query. Bodies over the value size limit are turned down without reading
+// them whole.
+func (h *HTTPTransport) bindRawPair(c *gin.Context, pair *Pair) error {
+	body := io.Reader(c.Request.Body)
+	limit := h.agent.config.MaxValueSize
+	if limit > 0 {
+		body = io.LimitReader(body, int64(limit)+1)
+	}
+
+	value, err := io.ReadAll(body)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if limit > 0 && len(value) > limit {
+		return &SizeError{Field: "value", Size: len(value), Limit: limit}
+	}
+	pair.Value = string(value)
+
+	ttl, err := parseInt(c, "ttl")
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if ttl != nil {
+		pair.TTL = *ttl
+	}
+
+	return nil
+}
+
+// pairIncrHandler adds ?incr=<delta> to, or subtracts ?decr=<delta> from,
+// a counter and returns its new value. The delta defaults to 1. With ?min=
+// and ?max= the call fails with 409 rather than leave the bounds, ?ttl=
+// sets the expiry of a counter the call creates.
+func (h *HTTPTransport) pairIncrHandler(c *gin.Context) {
+	req := &types.IncrRequest{Key: keyParam(c), Delta: 1}
+
+	_, incr := c.GetQuery("incr")
+	_, decr := c.GetQuery("decr")
+	if incr && decr {
+		_ = c.AbortWithError(http.StatusBadRequest, errIncrAndDecr)
+		return
+	}
+	param := "incr"
+	if decr {
+		param = "decr"
+	}
+
+	delta, err := parseInt(c, param)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+	if delta != nil {
+		req.Delta = *delta
+	}
+	if decr {
+		if req.Delta == math.MinInt64 {
+			_ = c.AbortWithError(http.StatusBadRequest, fmt.Errorf("invalid decr %d", req.Delta))
+			return
+		}
+		req.Delta = -req.Delta
+	}
+
+	if req.Min, err = parseInt(c, "min"); err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+	if req.Max, err = parseInt(c, "max"); err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+	ttl, err := parseInt(c, "ttl")
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+	if ttl != nil {
+		req.Ttl = *ttl
+	}
+
+	pair, err := h.client(c).Incr(req)
+	if err != nil {
+		h.renderWriteError(c, err)
+		return
+	}
+
+	setIndexHeader(c, pair.ModifyIndex)
+	renderJSON(c, http.StatusOK, pair)
+}
+
+// snapshotGetHandler streams a snapshot archive of the state. It is taken on
+// the leader unless ?stale is set.
+func (h *HTTPTransport) snapshotGetHandler(c *gin.Context) {
+	if _, stale := c.GetQuery("stale"); !stale && h.forwardToLeader(c) {
+		return
+	}
+
+	err := h.agent.saveSnapshot(c.Writer, func(meta *SnapshotMeta) {
+		setIndexHeader(c, meta.Index)
+		c.Header("Content-Type", "application/gzip")
+		c.Header(
+			"Content-Disposition",
+			fmt.Sprintf("attachment; filename=taskvault-%d.snap", meta.Index),
+		)
+		c.Status(http.StatusOK)
+	})
+	if err != nil {
+		if c.Writer.Written() {
+			// The archive is cut short, its checksums will not match.
+			h.logger.Error("api: snapshot failed while streaming", zap.Error(err))
+			return
+		}
+		h.renderReadError(c, err)
+	}
+}
+
+// snapshotPutHandler restores the state of the whole cluster from a
+// snapshot archive in the request body.
+func (h *HTTPTransport) snapshotPutHandler(c *gin.Context) {
+	if h.forwardToLeader(c) {
+		return
+	}
+
+	meta, err := h.agent.restoreSnapshot(c.Request.Body)
+	if err != nil {
+		if errors.Is(err, ErrSnapshotCorrupt) {
+			_ = c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+		h.renderReadError(c, err)
+		return
+	}
+
+	renderJSON(c, http.StatusOK, meta)
+}
+
+// transferLeaderHandler moves leadership to the server given by ?id=, or to
+// any other voter when it is omitted, and renders the new leader.
+func (h *HTTPTransport) transferLeaderHandler(c *gin.Context) {
+	if h.forwardToLeader(c) {
+		return
+	}
+
+	addr, id, err := h.agent.transferLeadership(raft.ServerID(c.Query("id")))
+	if err != nil {
+		if errors.Is(err, ErrUnknownPeer) || errors.Is(err, ErrNoTransferTarget) {
+			_ = c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+		if errors.Is(err, raft.ErrLeadershipTransferInProgress) {
+			_ = c.AbortWithError(http.StatusConflict, err)
+			return
+		}
+		h.renderReadError(c, err)
+		return
+	}
+
+	renderJSON(c, http.StatusOK, gin.H{
+		"ID":      id,
+		"Address": addr,
+	})
+}
+
+// autopilotHealthHandler renders the health of the servers as tracked by the
+// leader. The status is 429 while any server is unhealthy, so it can be used
+// as a health check.
+func (h *HTTPTransport) autopilotHealthHandler(c *gin.Context) {
+	if h.forwardToLeader(c) {
+		return
+	}
+
+	health := h.agent.autopilot.Health()
+	if health == nil {
+		_ = c.AbortWithError(
+			http.StatusServiceUnavailable, errors.New("server health is not known yet"),
+		)
+		return
+	}
+
+	status := http.StatusOK
+	if !health.Healthy {
+		status = http.StatusTooManyRequests
+	}
+	renderJSON(c, status, health)
+}
+
+// forwardToLeader proxies the request to the HTTP API of the leader when this
+// node is a follower, and reports whether it did.
+func (h *HTTPTransport) forwardToLeader(c *gin.Context) bool {
+	if h.agent.IsLeader() {
+		return false
+	}
+
+	if c.GetHeader(forwardedHeader) != "" {
+		_ = c.AbortWithError(http.StatusServiceUnavailable, raft.ErrNotLeader)
+		return true
+	}
+
+	addr, err := h.agent.leaderHTTPAddr()
+	if err != nil {
+		_ = c.AbortWithError(http.StatusServiceUnavailable, err)
+		return true
+	}
+
+	target := &url.URL{Scheme: "http", Host: addr}
+	var transport *http.Transport
+	if h.agent.config.HTTPS {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			_ = c.AbortWithError(http.StatusInternalServerError, err)
+			return true
+		}
+		target.Scheme = "https"
+		transport = &http.Transport{TLSClientConfig: h.agent.tls.OutgoingConfig(host)}
+		defer transport.CloseIdleConnections()
+	}
+
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	if transport != nil {
+		proxy.Transport = transport
+	}
+	c.Request.Header.Set(forwardedHeader, "true")
+	proxy.ServeHTTP(c.Writer, c.Request)
+
+	return true
+}
+
+// TxnOpBody is the JSON form of a transaction operation. Verb is one of get,
+// set, delete, check-index or check-exists. ValueEncoding is "base64" when
+// Value is base64 encoded.
+type TxnOpBody struct {
+	Verb          string
+	Key           string
+	Value         string
+	ValueEncoding string `json:",omitempty"`
+	Index         uint64
+	TTL           int64
+}
+
+func (h *HTTPTransport) txnHandler(c *gin.Context) {
+	var body []TxnOpBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	req := &types.TxnRequest{Ops: make([]*types.TxnOp, len(body))}
+	for i, op := range body {
+		t, err := parseTxnOpType(op.Verb)
+		if err != nil {
+			_ = c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+		value, err := decodeValue(op.Value, op.ValueEncoding)
+		if err != nil {
+			_ = c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+		req.Ops[i] = &types.TxnOp{
+			Type:  t,
+			Key:   op.Key,
+			Value: []byte(value),
+			Index: op.Index,
+			Ttl:   op.TTL,
+		}
+	}
+	if err := validateTxn(req); err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	resp, err := h.client(c).Txn(req)
+	if err != nil {
+		var txnErr *TxnError
+		if errors.As(err, &txnErr) {
+			renderJSON(c, http.StatusConflict, gin.H{
+				"OpIndex": txnErr.OpIndex,
+				"Error":   txnErr.Err.Error(),
+			})
+			c.Abort()
+			return
+		}
+		h.renderWriteError(c, err)
+		return
+	}
+
+	results := make([]Pair, len(resp.Results))
+	for i, p := range resp.Results {
+		results[i] = Pair{
+			Key:         p.Key,
+			Value:       string(p.Value),
+			CreateIndex: p.CreateIndex,
+			ModifyIndex: p.ModifyIndex,
+			TTL:         p.Ttl,
+			Session:     p.Session,
+			LockIndex:   p.LockIndex,
+			Meta:        keyMetaFromProto(p.Meta),
+		}
+	}
+
+	renderJSON(c, http.StatusOK, results)
+}
+
+// renderWriteError translates errors returned by write RPCs into HTTP
+// responses. CAS conflicts carry the current modify index so the client can
+// re-read and retry.
+func (h *HTTPTransport) renderWriteError(c *gin.Context, err error) {
+	var casErr *CASError
+	switch {
+	case errors.Is(err, ErrKeyNotFound):
+		_ = c.AbortWithError(http.StatusNotFound, err)
+	case errors.Is(err, ErrPermissionDenied):
+		_ = c.AbortWithError(http.StatusForbidden, err)
+	case errors.Is(err, ErrTooLarge):
+		_ = c.AbortWithError(http.StatusRequestEntityTooLarge, err)
+	case errors.Is(err, ErrLockConflict), errors.Is(err, ErrNotANumber), errors.Is(err, ErrOutOfBounds):
+		_ = c.AbortWithError(http.StatusConflict, err)
+	case isUnavailable(err):
+		_ = c.AbortWithError(http.StatusServiceUnavailable, err)
+	case status.Code(err) == codes.InvalidArgument, errors.Is(err, ErrSessionNotFound):
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+	case errors.As(err, &casErr):
+		setIndexHeader(c, casErr.ModifyIndex)
+		renderJSON(c, http.StatusConflict, casErr)
+		c.Abort()
+	default:
 		h.logger.Error(err)
 		c.AbortWithStatus(http.StatusInternalServerError)
 	}
+}
+
+// renderReadError translates errors returned by reads into HTTP responses.
+// Reads that no leader could serve are reported as unavailable so clients
+// can retry them, possibly with a weaker consistency.
+func (h *HTTPTransport) renderReadError(c *gin.Context, err error) {
+	if errors.Is(err, ErrPermissionDenied) {
+		_ = c.AbortWithError(http.StatusForbidden, err)
+		return
+	}
+	if isUnavailable(err) {
+		_ = c.AbortWithError(http.StatusServiceUnavailable, err)
+		return
+	}
+
+	h.logger.Error(err)
+	c.AbortWithStatus(http.StatusInternalServerError)
+}
+
+// client returns the gRPC client for writes, see Agent.requestClient.
+func (h *HTTPTransport) client(c *gin.Context) TaskvaultGRPCClient {
+	return h.agent.requestClient(c.Request.Context())
+}
+
+// parseCAS reads the optional ?cas=<index> query parameter.
+func parseCAS(c *gin.Context) (*uint64, error) {
+	v, ok := c.GetQuery("cas")
+	if !ok {
+		return nil, nil
+	}
+
+	cas, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cas index %q: %w", v, err)
+	}
+
+	return &cas, nil
+}
+
+// parseInt returns the integer query parameter name, nil when it is not
+// set or empty.
+func parseInt(c *gin.Context, name string) (*int64, error) {
+	v := c.Query(name)
+	if v == "" {
+		return nil, nil
+	}
+
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q: %w", name, v, err)
+	}
+
+	return &n, nil
+}
+
+// wantMeta reports whether a read asks for the metadata of keys with ?meta or
+// ?meta=true. Reads leave it out otherwise.
+func wantMeta(c *gin.Context) bool {
+	v, ok := c.GetQuery("meta")
+	if !ok {
+		return false
+	}
+	b, err := strconv.ParseBool(v)
+
+	return v == "" || err == nil && b
+}
+
+// keyParam returns the key of a storage route. Keys may contain slashes, so
+// the routes use a catch-all parameter.
+func keyParam(c *gin.Context) string {
+	return strings.TrimPrefix(c.Param("key"), "/")
+}
+
+func setIndexHeader(c *gin.Context, index uint64) {
+	c.Header(indexHeader, strconv.FormatUint(index, 10))
+}
 
-	c.Status(http.StatusCreated)
+func setQueryMetaHeaders(c *gin.Context, meta QueryMeta) {
+	c.Header(knownLeaderHeader, strconv.FormatBool(meta.KnownLeader))
+	c.Header(lastContactHeader, strconv.FormatInt(meta.LastContact.Milliseconds(), 10))
 }
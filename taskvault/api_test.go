@@ -0,0 +1,108 @@
+package taskvault
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	types2 "github.com/danluki/taskvault/pkg/types"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestCORSConfig(t *testing.T) {
+	config, err := corsConfig(nil)
+	require.NoError(t, err)
+	assert.Nil(t, config)
+
+	config, err = corsConfig([]string{"https://ui.example.com"})
+	require.NoError(t, err)
+	assert.False(t, config.AllowAllOrigins)
+	assert.Equal(t, []string{"https://ui.example.com"}, config.AllowOrigins)
+
+	config, err = corsConfig([]string{"*"})
+	require.NoError(t, err)
+	assert.True(t, config.AllowAllOrigins)
+
+	_, err = corsConfig([]string{"ui.example.com"})
+	assert.Error(t, err)
+}
+
+// pairsClient serves key writes from a map, as the leader would.
+type pairsClient struct {
+	TaskvaultGRPCClient
+	pairs map[string]string
+}
+
+func (p *pairsClient) WithToken(string) TaskvaultGRPCClient {
+	return p
+}
+
+func (p *pairsClient) WithContext(context.Context) TaskvaultGRPCClient {
+	return p
+}
+
+func (p *pairsClient) CreateValue(req *types2.CreateValueRequest) (*Pair, error) {
+	p.pairs[req.Key] = string(req.Value)
+	return &Pair{Key: req.Key, Value: string(req.Value)}, nil
+}
+
+func (p *pairsClient) UpdateValue(req *types2.UpdateValueRequest) (*Pair, error) {
+	if _, ok := p.pairs[req.Key]; !ok {
+		return nil, ErrKeyNotFound
+	}
+	p.pairs[req.Key] = string(req.Value)
+	return &Pair{Key: req.Key, Value: string(req.Value)}, nil
+}
+
+func TestPairPutHandler_RawBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	client := &pairsClient{pairs: map[string]string{}}
+	h := NewTransport(&Agent{config: DefaultConfig(), GRPCClient: client}, zap.NewNop().Sugar())
+	r := gin.New()
+	r.PUT("/v1/storage/*key", h.pairPutHandler)
+
+	put := func(url, contentType, body string) int {
+		req := httptest.NewRequest(http.MethodPut, url, strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Like JSON bodies, raw bodies only update existing keys.
+	assert.Equal(t, http.StatusNotFound, put("/v1/storage/blob", octetStream, "\x00\xff"))
+	assert.Equal(t, http.StatusNotFound, put("/v1/storage/blob", "application/json", `{"Value":"a"}`))
+	assert.Empty(t, client.pairs)
+
+	assert.Equal(t, http.StatusOK, put("/v1/storage/blob?create", octetStream, "\x00\xff"))
+	assert.Equal(t, "\x00\xff", client.pairs["blob"])
+	assert.Equal(t, http.StatusOK, put("/v1/storage/blob", octetStream, "\x01"))
+	assert.Equal(t, "\x01", client.pairs["blob"])
+}
+
+func TestPair_JSONValue(t *testing.T) {
+	b, err := json.Marshal(Pair{Key: "a", Value: "text"})
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"Value":"text"`)
+	assert.NotContains(t, string(b), "ValueEncoding")
+
+	binary := "\x00\xff\xfe"
+	b, err = json.Marshal([]Pair{{Key: "a", Value: binary}})
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"Value":"AP/+"`)
+	assert.Contains(t, string(b), `"ValueEncoding":"base64"`)
+
+	var pairs []Pair
+	require.NoError(t, json.Unmarshal(b, &pairs))
+	require.Len(t, pairs, 1)
+	assert.Equal(t, binary, pairs[0].Value)
+
+	var p Pair
+	assert.Error(t, json.Unmarshal([]byte(`{"Value":"x","ValueEncoding":"hex"}`), &p))
+}
@@ -0,0 +1,330 @@
+package taskvault
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/danluki/taskvault/pkg/types"
+	metrics "github.com/hashicorp/go-metrics"
+	"github.com/hashicorp/raft"
+	"github.com/hashicorp/serf/serf"
+	"go.uber.org/zap"
+)
+
+// ServerHealth is the health of a server as seen by the leader.
+type ServerHealth struct {
+	ID         string
+	Name       string
+	Address    string
+	SerfStatus string
+	Leader     bool
+	Voter      bool
+	// LastContact is the time since the server last heard from the leader,
+	// negative when it never did or could not be asked.
+	LastContact time.Duration
+	LastTerm    uint64
+	LastIndex   uint64
+	Healthy     bool
+	// StableSince is when the server last turned healthy or unhealthy.
+	StableSince time.Time
+}
+
+// OperatorHealth is the health of the cluster as seen by the leader.
+type OperatorHealth struct {
+	Healthy bool
+	// FailureTolerance is how many voters can fail without losing quorum.
+	FailureTolerance int
+	Servers          []ServerHealth
+}
+
+// autopilot runs on the leader. It tracks the health of every server of the
+// Raft configuration and removes servers that have been failed for longer
+// than the dead server threshold, as long as that is safe for the quorum.
+type autopilot struct {
+	agent *Agent
+
+	lock   sync.RWMutex
+	health *OperatorHealth
+	// failedSince is when each failed server was first seen failed.
+	failedSince map[raft.ServerID]time.Time
+}
+
+func newAutopilot(agent *Agent) *autopilot {
+	return &autopilot{
+		agent:       agent,
+		failedSince: make(map[raft.ServerID]time.Time),
+	}
+}
+
+// Health returns the health computed by the last run, or nil when this node
+// is not the leader or has not run yet.
+func (ap *autopilot) Health() *OperatorHealth {
+	ap.lock.RLock()
+	defer ap.lock.RUnlock()
+
+	return ap.health
+}
+
+// reset forgets everything learned, it is called when leadership is lost.
+func (ap *autopilot) reset() {
+	ap.lock.Lock()
+	defer ap.lock.Unlock()
+
+	ap.health = nil
+	ap.failedSince = make(map[raft.ServerID]time.Time)
+}
+
+// run updates the health of the servers and removes the dead ones.
+func (ap *autopilot) run() error {
+	defer metrics.MeasureSince([]string{"taskvault", "autopilot", "run"}, time.Now())
+
+	a := ap.agent
+	configFuture := a.raft.GetConfiguration()
+	if err := configFuture.Error(); err != nil {
+		return err
+	}
+	servers := configFuture.Configuration().Servers
+
+	members := make(map[raft.ServerID]serf.Member)
+	for _, member := range a.serf.Members() {
+		if parts := toServerPart(member); parts != nil {
+			members[raft.ServerID(parts.ID)] = member
+		}
+	}
+
+	now := time.Now()
+	health := ap.serverHealth(servers, members, now)
+
+	ap.lock.Lock()
+	ap.health = health
+	failedSince := make(map[raft.ServerID]time.Time)
+	for _, server := range servers {
+		member, ok := members[server.ID]
+		if ok && member.Status != serf.StatusFailed && member.Status != serf.StatusLeft {
+			continue
+		}
+		since, ok := ap.failedSince[server.ID]
+		if !ok {
+			since = now
+		}
+		failedSince[server.ID] = since
+	}
+	ap.failedSince = failedSince
+	ap.lock.Unlock()
+
+	metrics.SetGauge([]string{"taskvault", "autopilot", "healthy"}, boolToFloat(health.Healthy))
+	metrics.SetGauge(
+		[]string{"taskvault", "autopilot", "failure_tolerance"},
+		float32(health.FailureTolerance),
+	)
+
+	if !a.config.AutopilotCleanupDeadServers {
+		return nil
+	}
+
+	dead, safe := deadServers(servers, failedSince, a.config.AutopilotDeadServerThreshold, now)
+	if !safe {
+		a.logger.Warn(
+			"taskvault: autopilot: refusing to remove dead servers, too many voters are failed",
+		)
+		return nil
+	}
+	for _, id := range dead {
+		a.logger.With(zap.String("id", string(id))).
+			Info("taskvault: autopilot: removing dead server")
+		if err := a.removeRaftPeerByID(id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ap *autopilot) serverHealth(
+	servers []raft.Server, members map[raft.ServerID]serf.Member, now time.Time,
+) *OperatorHealth {
+	a := ap.agent
+	self := raft.ServerID(a.config.NodeName)
+	leader := localRaftStats(a)
+	followers := ap.serverStats(servers, members, self)
+
+	prev := make(map[string]ServerHealth)
+	if old := ap.Health(); old != nil {
+		for _, h := range old.Servers {
+			prev[h.ID] = h
+		}
+	}
+
+	health := &OperatorHealth{Healthy: true}
+	voters, healthyVoters := 0, 0
+	for _, server := range servers {
+		h := ServerHealth{
+			ID:          string(server.ID),
+			Name:        "unknown",
+			Address:     string(server.Address),
+			SerfStatus:  serf.StatusNone.String(),
+			Leader:      server.ID == self,
+			Voter:       server.Suffrage == raft.Voter,
+			LastContact: -1,
+		}
+
+		member, ok := members[server.ID]
+		if ok {
+			h.Name = member.Name
+			h.SerfStatus = member.Status.String()
+		}
+
+		stats := followers[server.ID]
+		if h.Leader {
+			stats = leader
+		}
+		if stats != nil {
+			h.LastIndex = stats.LastIndex
+			h.LastTerm = stats.LastTerm
+			if stats.LastContact >= 0 {
+				h.LastContact = time.Duration(stats.LastContact) * time.Millisecond
+			}
+		}
+
+		h.Healthy = serverHealthy(&h, leader, a.config)
+		h.StableSince = now
+		if p, ok := prev[h.ID]; ok && p.Healthy == h.Healthy {
+			h.StableSince = p.StableSince
+		}
+
+		if h.Voter {
+			voters++
+			if h.Healthy {
+				healthyVoters++
+			}
+		}
+		if !h.Healthy {
+			health.Healthy = false
+		}
+		health.Servers = append(health.Servers, h)
+	}
+
+	if tolerance := healthyVoters - (voters/2 + 1); tolerance > 0 {
+		health.FailureTolerance = tolerance
+	}
+
+	return health
+}
+
+// serverStats asks the alive servers other than self for their Raft stats.
+// The calls run concurrently under one deadline, so slow servers hold up the
+// leader loop for raftStatsTimeout at most. Servers that do not answer are
+// left out.
+func (ap *autopilot) serverStats(
+	servers []raft.Server, members map[raft.ServerID]serf.Member, self raft.ServerID,
+) map[raft.ServerID]*types.RaftStatsResponse {
+	a := ap.agent
+
+	ctx, cancel := context.WithTimeout(context.Background(), raftStatsTimeout)
+	defer cancel()
+	client := a.serverClient().WithContext(ctx)
+
+	var (
+		lock  sync.Mutex
+		wg    sync.WaitGroup
+		stats = make(map[raft.ServerID]*types.RaftStatsResponse)
+	)
+	for _, server := range servers {
+		member, ok := members[server.ID]
+		if server.ID == self || !ok || member.Status != serf.StatusAlive {
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			s, err := client.RaftStats(string(server.Address))
+			if err != nil {
+				a.logger.With(zap.Error(err), zap.String("id", string(server.ID))).
+					Debug("taskvault: autopilot: failed to get server stats")
+				return
+			}
+
+			lock.Lock()
+			stats[server.ID] = s
+			lock.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	return stats
+}
+
+// serverHealthy reports whether a server is alive and keeps up with the
+// leader.
+func serverHealthy(h *ServerHealth, leader *types.RaftStatsResponse, config *Config) bool {
+	if h.SerfStatus != serf.StatusAlive.String() {
+		return false
+	}
+	if h.LastContact < 0 || h.LastContact > config.AutopilotLastContactThreshold {
+		return false
+	}
+	if h.LastTerm != leader.LastTerm {
+		return false
+	}
+
+	return h.LastIndex+config.AutopilotMaxTrailingLogs >= leader.LastIndex
+}
+
+// deadServers returns the servers that have been failed for at least
+// threshold. Removing them is only safe while fewer than half of the voters
+// are failed, otherwise this leader might be the one cut off and safe is
+// false.
+func deadServers(
+	servers []raft.Server,
+	failedSince map[raft.ServerID]time.Time,
+	threshold time.Duration,
+	now time.Time,
+) (dead []raft.ServerID, safe bool) {
+	voters, failedVoters := 0, 0
+	for _, server := range servers {
+		since, failed := failedSince[server.ID]
+		if server.Suffrage == raft.Voter {
+			voters++
+			if failed {
+				failedVoters++
+			}
+		}
+		if failed && now.Sub(since) >= threshold {
+			dead = append(dead, server.ID)
+		}
+	}
+
+	if failedVoters*2 >= voters {
+		return nil, false
+	}
+
+	return dead, true
+}
+
+// localRaftStats returns the Raft progress of this node.
+func localRaftStats(a *Agent) *types.RaftStatsResponse {
+	stats := &types.RaftStatsResponse{
+		LastIndex:   a.raft.LastIndex(),
+		LastContact: -1,
+	}
+	stats.LastTerm, _ = strconv.ParseUint(a.raft.Stats()["last_log_term"], 10, 64)
+
+	if a.IsLeader() {
+		stats.LastContact = 0
+	} else if last := a.raft.LastContact(); !last.IsZero() {
+		stats.LastContact = time.Since(last).Milliseconds()
+	}
+
+	return stats
+}
+
+func boolToFloat(b bool) float32 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,118 @@
+package taskvault
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/danluki/taskvault/pkg/types"
+	"github.com/hashicorp/raft"
+	"github.com/hashicorp/serf/serf"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestServerHealthy(t *testing.T) {
+	c := DefaultConfig()
+	leader := &types.RaftStatsResponse{LastIndex: 1000, LastTerm: 3}
+
+	h := &ServerHealth{
+		SerfStatus:  serf.StatusAlive.String(),
+		LastContact: 50 * time.Millisecond,
+		LastTerm:    3,
+		LastIndex:   990,
+	}
+	assert.True(t, serverHealthy(h, leader, c))
+
+	lagging := *h
+	lagging.LastIndex = 500
+	assert.False(t, serverHealthy(&lagging, leader, c))
+
+	stale := *h
+	stale.LastContact = time.Second
+	assert.False(t, serverHealthy(&stale, leader, c))
+
+	unknown := *h
+	unknown.LastContact = -1
+	assert.False(t, serverHealthy(&unknown, leader, c))
+
+	oldTerm := *h
+	oldTerm.LastTerm = 2
+	assert.False(t, serverHealthy(&oldTerm, leader, c))
+
+	failed := *h
+	failed.SerfStatus = serf.StatusFailed.String()
+	assert.False(t, serverHealthy(&failed, leader, c))
+}
+
+func TestDeadServers(t *testing.T) {
+	now := time.Now()
+	servers := []raft.Server{
+		{ID: "a", Suffrage: raft.Voter},
+		{ID: "b", Suffrage: raft.Voter},
+		{ID: "c", Suffrage: raft.Voter},
+	}
+
+	dead, safe := deadServers(servers, map[raft.ServerID]time.Time{
+		"c": now.Add(-time.Minute),
+	}, 2*time.Minute, now)
+	assert.True(t, safe)
+	assert.Empty(t, dead)
+
+	dead, safe = deadServers(servers, map[raft.ServerID]time.Time{
+		"c": now.Add(-3 * time.Minute),
+	}, 2*time.Minute, now)
+	assert.True(t, safe)
+	assert.Equal(t, []raft.ServerID{"c"}, dead)
+
+	// With two of three voters failed this leader may be the one cut off.
+	dead, safe = deadServers(servers, map[raft.ServerID]time.Time{
+		"b": now.Add(-3 * time.Minute),
+		"c": now.Add(-3 * time.Minute),
+	}, 2*time.Minute, now)
+	assert.False(t, safe)
+	assert.Empty(t, dead)
+}
+
+// slowStatsClient answers RaftStats after delay, as a loaded server would.
+type slowStatsClient struct {
+	TaskvaultGRPCClient
+	delay time.Duration
+}
+
+func (c *slowStatsClient) WithToken(string) TaskvaultGRPCClient {
+	return c
+}
+
+func (c *slowStatsClient) WithContext(context.Context) TaskvaultGRPCClient {
+	return c
+}
+
+func (c *slowStatsClient) RaftStats(addr string) (*types.RaftStatsResponse, error) {
+	time.Sleep(c.delay)
+	return &types.RaftStatsResponse{LastIndex: 7}, nil
+}
+
+func TestAutopilot_ServerStatsConcurrent(t *testing.T) {
+	a := &Agent{
+		config:     DefaultConfig(),
+		GRPCClient: &slowStatsClient{delay: 300 * time.Millisecond},
+		logger:     zap.NewNop().Sugar(),
+	}
+	ap := &autopilot{agent: a}
+
+	var servers []raft.Server
+	members := make(map[raft.ServerID]serf.Member)
+	for _, id := range []raft.ServerID{"n1", "n2", "n3", "n4"} {
+		servers = append(servers, raft.Server{ID: id, Address: raft.ServerAddress(id)})
+		members[id] = serf.Member{Status: serf.StatusAlive}
+	}
+	members["n4"] = serf.Member{Status: serf.StatusFailed}
+
+	start := time.Now()
+	stats := ap.serverStats(servers, members, "n1")
+	assert.Less(t, time.Since(start), 600*time.Millisecond, "calls run concurrently")
+	assert.Len(t, stats, 2)
+	assert.Equal(t, uint64(7), stats["n2"].LastIndex)
+	assert.NotContains(t, stats, raft.ServerID("n4"))
+}
@@ -28,6 +28,43 @@ type Config struct {
 
 	EncryptKey string `mapstructure:"encrypt"`
 
+	// CertFile, KeyFile and CAFile enable TLS on the RPC port, which carries
+	// both Raft and gRPC. Servers verify each other's certificates against
+	// the CA, so the certificate must be valid for server and client auth.
+	CertFile string `mapstructure:"cert-file"`
+
+	KeyFile string `mapstructure:"key-file"`
+
+	CAFile string `mapstructure:"ca-file"`
+
+	// HTTPS serves the HTTP API over TLS with the certificate in CertFile.
+	HTTPS bool `mapstructure:"https"`
+
+	// HTTPVerifyClients requires clients of the HTTP API to present a
+	// certificate signed by the CA in CAFile.
+	HTTPVerifyClients bool `mapstructure:"http-verify-clients"`
+
+	// CORSAllowedOrigins are the origins browsers may call the HTTP API
+	// from, "*" allows any. Cross-origin requests are refused when empty.
+	CORSAllowedOrigins []string `mapstructure:"cors-allowed-origins"`
+
+	// ACLEnabled requires requests to carry a token whose policies grant
+	// access to what they touch.
+	ACLEnabled bool `mapstructure:"acl-enabled"`
+
+	// ACLDefaultPolicy is what requests get where no policy rule applies,
+	// either "allow" or "deny".
+	ACLDefaultPolicy string `mapstructure:"acl-default-policy"`
+
+	// ACLAgentToken is a secret shared by the servers, sent on the calls
+	// they make to each other to bootstrap and track health. It grants
+	// operator read and must be the same on every server.
+	ACLAgentToken string `mapstructure:"acl-agent-token"`
+
+	// TLSServerName is the name server certificates are verified against.
+	// When empty they must be valid for the host they are dialed by.
+	TLSServerName string `mapstructure:"tls-server-name"`
+
 	StartJoin []string `mapstructure:"join"`
 
 	RetryJoin []string `mapstructure:"retry-join"`
@@ -48,10 +85,68 @@ type Config struct {
 
 	DataDir string `mapstructure:"data-dir"`
 
+	// Replica makes the server join as a non-voting member. It receives the
+	// log and serves stale reads but does not count toward the quorum.
+	Replica bool
+
+	// StoreBackend is where the state store keeps its data, either
+	// StoreBackendFile under DataDir or StoreBackendMemory.
+	StoreBackend string `mapstructure:"store-backend"`
+
+	// StoreSync is the fsync policy of the file backend: always,
+	// everysecond or never.
+	StoreSync string `mapstructure:"store-sync"`
+
+	// MaxKeySize and MaxValueSize bound the size in bytes of the keys and
+	// values of writes, 0 for no limit. Larger writes are rejected before
+	// they reach the Raft log.
+	MaxKeySize   int `mapstructure:"max-key-size"`
+	MaxValueSize int `mapstructure:"max-value-size"`
+
 	DevMode bool
 
 	RefreshInterval time.Duration
 
+	// ExpiryInterval is how often the leader looks for keys whose TTL ran out.
+	ExpiryInterval time.Duration
+
+	// JobInterval is how often the leader looks for jobs that are due.
+	JobInterval time.Duration
+
+	// AutopilotCleanupDeadServers enables the removal of servers that have
+	// been failed for longer than AutopilotDeadServerThreshold.
+	AutopilotCleanupDeadServers bool `mapstructure:"autopilot-cleanup-dead-servers"`
+
+	AutopilotDeadServerThreshold time.Duration `mapstructure:"autopilot-dead-server-threshold"`
+
+	// AutopilotLastContactThreshold and AutopilotMaxTrailingLogs bound how
+	// far behind the leader a server may fall and still be healthy.
+	AutopilotLastContactThreshold time.Duration `mapstructure:"autopilot-last-contact-threshold"`
+
+	AutopilotMaxTrailingLogs uint64 `mapstructure:"autopilot-max-trailing-logs"`
+
+	// AutopilotInterval is how often the leader checks the servers.
+	AutopilotInterval time.Duration
+
+	// LeaveTransferTimeout bounds the leadership transfer that starts a
+	// graceful leave of the leader.
+	LeaveTransferTimeout time.Duration `mapstructure:"leave-transfer-timeout"`
+
+	// LeaveBroadcastTimeout bounds how long the leave message is gossiped
+	// before serf gives up on it.
+	LeaveBroadcastTimeout time.Duration `mapstructure:"leave-broadcast-timeout"`
+
+	// LeaveRaftTimeout bounds the wait for the leader to remove this server
+	// from the Raft configuration.
+	LeaveRaftTimeout time.Duration `mapstructure:"leave-raft-timeout"`
+
+	// LeaveDrainTimeout bounds the wait for in-flight HTTP and gRPC
+	// requests, the remaining ones are cut off.
+	LeaveDrainTimeout time.Duration `mapstructure:"leave-drain-timeout"`
+
+	// LeaveStoreTimeout bounds closing the state store.
+	LeaveStoreTimeout time.Duration `mapstructure:"leave-store-timeout"`
+
 	SerfReconnectTimeout string `mapstructure:"serf-reconnect-timeout"`
 
 	EnablePrometheus bool `mapstructure:"enable-prometheus"`
@@ -59,6 +154,11 @@ type Config struct {
 	UI bool
 }
 
+const (
+	StoreBackendFile   = "file"
+	StoreBackendMemory = "memory"
+)
+
 const (
 	DefaultBindPort      int           = 8946
 	DefaultRPCPort       int           = 6868
@@ -83,9 +183,28 @@ func DefaultConfig() *Config {
 		LogLevel:             "info",
 		RPCPort:              DefaultRPCPort,
 		DataDir:              "taskvault.data",
+		StoreBackend:         StoreBackendFile,
+		StoreSync:            SyncEverySecond,
+		MaxKeySize:           1024,
+		MaxValueSize:         512 * 1024,
 		RefreshInterval:      10 * time.Second,
+		ExpiryInterval:       time.Second,
+		JobInterval:          time.Second,
 		SerfReconnectTimeout: "24h",
 		UI:                   true,
+		ACLDefaultPolicy:     ACLPolicyDeny,
+
+		AutopilotCleanupDeadServers:   true,
+		AutopilotDeadServerThreshold:  5 * time.Minute,
+		AutopilotLastContactThreshold: 200 * time.Millisecond,
+		AutopilotMaxTrailingLogs:      250,
+		AutopilotInterval:             2 * time.Second,
+
+		LeaveTransferTimeout:  5 * time.Second,
+		LeaveBroadcastTimeout: 5 * time.Second,
+		LeaveRaftTimeout:      10 * time.Second,
+		LeaveDrainTimeout:     10 * time.Second,
+		LeaveStoreTimeout:     5 * time.Second,
 	}
 }
 
@@ -134,6 +253,46 @@ func ConfigFlagSet() *flag.FlagSet {
 		"encrypt", "",
 		"16 bytes value",
 	)
+	cmdFlags.String(
+		"cert-file", "",
+		"PEM encoded certificate of this server, enables TLS on the RPC port",
+	)
+	cmdFlags.String(
+		"key-file", "",
+		"PEM encoded private key of cert-file",
+	)
+	cmdFlags.String(
+		"ca-file", "",
+		"PEM encoded CA certificates used to verify other servers",
+	)
+	cmdFlags.String(
+		"tls-server-name", "",
+		"Name server certificates are verified against, the dialed host by default",
+	)
+	cmdFlags.Bool(
+		"https", false,
+		"Serve the HTTP API over TLS, requires cert-file, key-file and ca-file",
+	)
+	cmdFlags.Bool(
+		"http-verify-clients", false,
+		"Require HTTP API clients to present a certificate signed by ca-file",
+	)
+	cmdFlags.StringSlice(
+		"cors-allowed-origins", []string{},
+		"Origins browsers may call the HTTP API from, * allows any",
+	)
+	cmdFlags.Bool(
+		"acl-enabled", false,
+		"Require ACL tokens on the HTTP API and gRPC",
+	)
+	cmdFlags.String(
+		"acl-default-policy", c.ACLDefaultPolicy,
+		"Access granted where no ACL rule applies (allow|deny)",
+	)
+	cmdFlags.String(
+		"acl-agent-token", "",
+		"Secret servers send on calls to each other, the same on every server",
+	)
 	cmdFlags.String(
 		"log-level", c.LogLevel,
 		"Log level (debug|info|warn|error|fatal|panic)",
@@ -154,14 +313,70 @@ func ConfigFlagSet() *flag.FlagSet {
 		"data-dir", c.DataDir,
 		``,
 	)
+	cmdFlags.String(
+		"store-backend", c.StoreBackend,
+		"State store backend (file|memory)",
+	)
+	cmdFlags.String(
+		"store-sync", c.StoreSync,
+		"Fsync policy of the file store backend (always|everysecond|never)",
+	)
+	cmdFlags.Int(
+		"max-key-size", c.MaxKeySize,
+		"Maximum size of a key in bytes, 0 for no limit",
+	)
+	cmdFlags.Int(
+		"max-value-size", c.MaxValueSize,
+		"Maximum size of a value in bytes, 0 for no limit",
+	)
 	cmdFlags.String(
 		"serf-reconnect-timeout", c.SerfReconnectTimeout,
 		``,
 	)
+	cmdFlags.Bool(
+		"autopilot-cleanup-dead-servers", c.AutopilotCleanupDeadServers,
+		"Remove servers that have been failed for longer than the dead server threshold",
+	)
+	cmdFlags.String(
+		"autopilot-dead-server-threshold", c.AutopilotDeadServerThreshold.String(),
+		"How long a server must be failed before it is removed",
+	)
+	cmdFlags.String(
+		"autopilot-last-contact-threshold", c.AutopilotLastContactThreshold.String(),
+		"Maximum time since a healthy server last heard from the leader",
+	)
+	cmdFlags.Uint64(
+		"autopilot-max-trailing-logs", c.AutopilotMaxTrailingLogs,
+		"Maximum number of log entries a healthy server may trail the leader by",
+	)
+	cmdFlags.String(
+		"leave-transfer-timeout", c.LeaveTransferTimeout.String(),
+		"How long the leader tries to hand over leadership when it leaves",
+	)
+	cmdFlags.String(
+		"leave-broadcast-timeout", c.LeaveBroadcastTimeout.String(),
+		"How long the leave message is gossiped to the cluster",
+	)
+	cmdFlags.String(
+		"leave-raft-timeout", c.LeaveRaftTimeout.String(),
+		"How long to wait for the leader to remove this server from Raft when it leaves",
+	)
+	cmdFlags.String(
+		"leave-drain-timeout", c.LeaveDrainTimeout.String(),
+		"How long in-flight HTTP and gRPC requests may take to finish when leaving",
+	)
+	cmdFlags.String(
+		"leave-store-timeout", c.LeaveStoreTimeout.String(),
+		"How long closing the state store may take when leaving",
+	)
 	cmdFlags.Bool(
 		"bootstrap", false,
 		"Bootstrap the cluster.",
 	)
+	cmdFlags.Bool(
+		"replica", false,
+		"Join as a non-voting replica that does not count toward the quorum",
+	)
 	cmdFlags.Bool(
 		"ui", true,
 		"",
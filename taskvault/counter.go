@@ -0,0 +1,90 @@
+package taskvault
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/buntdb"
+)
+
+var (
+	ErrNotANumber  = errors.New("value is not an integer")
+	ErrOutOfBounds = errors.New("counter out of bounds")
+
+	errIncrAndDecr = errors.New("incr and decr can not be combined")
+)
+
+// IncrOptions are the arguments of IncrValue besides the key.
+type IncrOptions struct {
+	Delta    int64
+	Min, Max *int64
+	// ExpiresAt applies to a counter created by the call.
+	ExpiresAt int64
+	// Now decides whether the counter expired. It comes from the leader so
+	// every replica sees the same counter.
+	Now   int64
+	Index uint64
+}
+
+// IncrValue adds a delta to the integer value of key and returns the new
+// value. A missing or expired key counts as 0. When the new value would
+// leave the bounds, or overflow, the counter is left unchanged and the call
+// fails with ErrOutOfBounds.
+func (s *Store) IncrValue(key string, opts IncrOptions) (*Pair, error) {
+	if isReservedKey(key) {
+		return nil, ErrReservedKey
+	}
+
+	var pair Pair
+
+	err := s.db.Update(func(tx *buntdb.Tx) error {
+		current, err := getEntry(tx, key)
+		if err != nil {
+			return err
+		}
+		if current != nil && current.ExpiresAt != 0 && current.ExpiresAt <= opts.Now {
+			current = nil
+		}
+
+		var n int64
+		expiresAt := opts.ExpiresAt
+		if current != nil {
+			n, err = strconv.ParseInt(strings.TrimSpace(current.Value), 10, 64)
+			if err != nil {
+				return fmt.Errorf("%w: %q", ErrNotANumber, current.Value)
+			}
+			expiresAt = current.ExpiresAt
+		}
+
+		if opts.Delta > 0 && n > math.MaxInt64-opts.Delta ||
+			opts.Delta < 0 && n < math.MinInt64-opts.Delta {
+			return fmt.Errorf("%w: %d%+d overflows", ErrOutOfBounds, n, opts.Delta)
+		}
+		n += opts.Delta
+		if opts.Min != nil && n < *opts.Min {
+			return fmt.Errorf("%w: %d is below %d", ErrOutOfBounds, n, *opts.Min)
+		}
+		if opts.Max != nil && n > *opts.Max {
+			return fmt.Errorf("%w: %d is above %d", ErrOutOfBounds, n, *opts.Max)
+		}
+
+		e, err := putEntry(tx, key, current, strconv.FormatInt(n, 10), WriteOptions{
+			Index:     opts.Index,
+			ExpiresAt: expiresAt,
+		})
+		if err != nil {
+			return err
+		}
+		pair = e.pair(key)
+
+		return setAppliedIndex(tx, opts.Index)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pair, nil
+}
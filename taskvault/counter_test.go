@@ -0,0 +1,78 @@
+package taskvault
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func bound(n int64) *int64 {
+	return &n
+}
+
+func TestStore_IncrValue(t *testing.T) {
+	s := newTestStore(t)
+
+	p, err := s.IncrValue("hits", IncrOptions{Delta: 5, Index: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "5", p.Value)
+
+	p, err = s.IncrValue("hits", IncrOptions{Delta: -7, Index: 2})
+	require.NoError(t, err)
+	assert.Equal(t, "-2", p.Value)
+	assert.Equal(t, uint64(1), p.CreateIndex)
+
+	// Out of bounds calls leave the counter alone.
+	_, err = s.IncrValue("hits", IncrOptions{Delta: -1, Min: bound(-2), Index: 3})
+	assert.ErrorIs(t, err, ErrOutOfBounds)
+	_, err = s.IncrValue("hits", IncrOptions{Delta: 3, Max: bound(0), Index: 3})
+	assert.ErrorIs(t, err, ErrOutOfBounds)
+	p, err = s.GetValue("hits")
+	require.NoError(t, err)
+	assert.Equal(t, "-2", p.Value)
+
+	_, err = s.SetValue("max", "9223372036854775807", WriteOptions{Index: 4})
+	require.NoError(t, err)
+	_, err = s.IncrValue("max", IncrOptions{Delta: 1, Index: 5})
+	assert.ErrorIs(t, err, ErrOutOfBounds)
+	_, err = s.IncrValue("max", IncrOptions{Delta: math.MinInt64, Index: 5})
+	assert.NoError(t, err)
+
+	_, err = s.SetValue("text", "abc", WriteOptions{Index: 6})
+	require.NoError(t, err)
+	_, err = s.IncrValue("text", IncrOptions{Delta: 1, Index: 7})
+	assert.ErrorIs(t, err, ErrNotANumber)
+
+	_, err = s.IncrValue(reservedPrefix+"x", IncrOptions{Delta: 1, Index: 7})
+	assert.ErrorIs(t, err, ErrReservedKey)
+}
+
+func TestStore_IncrValueExpiry(t *testing.T) {
+	s := newTestStore(t)
+	now := time.Now()
+	window := now.Add(time.Minute).UnixNano()
+
+	p, err := s.IncrValue("bucket", IncrOptions{Delta: 1, ExpiresAt: window, Now: now.UnixNano(), Index: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(60), p.TTL)
+
+	// The TTL only applies to a new counter.
+	_, err = s.IncrValue("bucket", IncrOptions{
+		Delta: 1, ExpiresAt: now.Add(time.Hour).UnixNano(), Now: now.UnixNano(), Index: 2,
+	})
+	require.NoError(t, err)
+	p, err = s.GetValue("bucket")
+	require.NoError(t, err)
+	assert.Equal(t, "2", p.Value)
+	assert.Equal(t, int64(60), p.TTL)
+
+	// Once the window is over the counter starts over.
+	p, err = s.IncrValue("bucket", IncrOptions{Delta: 1, Now: window, Index: 3})
+	require.NoError(t, err)
+	assert.Equal(t, "1", p.Value)
+	assert.Equal(t, uint64(3), p.CreateIndex)
+	assert.Zero(t, p.TTL)
+}
@@ -0,0 +1,52 @@
+package taskvault
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCron_Next(t *testing.T) {
+	from := time.Date(2024, time.March, 15, 10, 30, 0, 0, time.UTC)
+
+	for spec, want := range map[string]time.Time{
+		"*/15 * * * *":     time.Date(2024, time.March, 15, 10, 45, 0, 0, time.UTC),
+		"0 9 * * mon-fri":  time.Date(2024, time.March, 18, 9, 0, 0, 0, time.UTC),
+		"0 0 1,15 * *":     time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC),
+		"30 10 * * *":      time.Date(2024, time.March, 16, 10, 30, 0, 0, time.UTC),
+		"0 0 29 feb *":     time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC),
+		"0 0 13 * 5":       time.Date(2024, time.March, 22, 0, 0, 0, 0, time.UTC),
+		"*/10 30 10 * * *": time.Date(2024, time.March, 15, 10, 30, 10, 0, time.UTC),
+		"@hourly":          time.Date(2024, time.March, 15, 11, 0, 0, 0, time.UTC),
+		"@weekly":          time.Date(2024, time.March, 17, 0, 0, 0, 0, time.UTC),
+		"0 12 * * 7":       time.Date(2024, time.March, 17, 12, 0, 0, 0, time.UTC),
+		"@every 90s":       from.Add(90 * time.Second),
+	} {
+		s, err := parseCron(spec)
+		require.NoError(t, err, spec)
+		assert.Equal(t, want, s.next(from), spec)
+	}
+
+	s, err := parseCron("0 0 30 feb *")
+	require.NoError(t, err)
+	assert.True(t, s.next(from).IsZero())
+}
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* * * * * * *",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"* * * foo *",
+		"@every 10ms",
+		"@every soon",
+	} {
+		_, err := parseCron(spec)
+		assert.Error(t, err, spec)
+	}
+}
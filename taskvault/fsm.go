@@ -1,7 +1,12 @@
 package taskvault
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
 	"io"
+	"time"
+	"unicode/utf8"
 
 	"github.com/danluki/taskvault/pkg/types"
 	"github.com/hashicorp/raft"
@@ -15,11 +20,94 @@ const (
 	AddPairType MessageType = iota
 	DeletePairType
 	UpdatePairType
+	TxnType
+	ACLBootstrapType
+	ACLTokenSetType
+	ACLTokenDeleteType
+	ACLPolicySetType
+	ACLPolicyDeleteType
+	SessionCreateType
+	SessionRenewType
+	SessionDestroyType
+	QueueEnqueueType
+	QueueClaimType
+	QueueAckType
+	QueueNackType
+	QueueDeleteType
+	JobSetType
+	JobDeleteType
+	JobRunType
+	IncrType
 )
 
 type Pair struct {
-	Key   string
-	Value string
+	Key         string
+	Value       string
+	CreateIndex uint64
+	ModifyIndex uint64
+	// TTL is the time to live in seconds. On reads it is the time left.
+	TTL int64
+	// Session holds the lock of the key, empty when it is not locked.
+	Session string
+	// LockIndex counts the times the lock of the key was acquired.
+	LockIndex uint64
+	// Meta is the metadata of the key, nil when it has none.
+	Meta *KeyMeta `json:",omitempty"`
+}
+
+// valueEncodingBase64 marks a value base64 encoded in JSON. Values that are
+// not valid UTF-8 are sent that way, a JSON string can not hold them.
+const valueEncodingBase64 = "base64"
+
+// pairJSON is the JSON form of a Pair, ValueEncoding is set when Value is
+// encoded.
+type pairJSON struct {
+	pairFields
+	ValueEncoding string `json:",omitempty"`
+}
+
+type pairFields Pair
+
+func (p Pair) MarshalJSON() ([]byte, error) {
+	v := pairJSON{pairFields: pairFields(p)}
+	if !utf8.ValidString(p.Value) {
+		v.Value = base64.StdEncoding.EncodeToString([]byte(p.Value))
+		v.ValueEncoding = valueEncodingBase64
+	}
+
+	return json.Marshal(v)
+}
+
+func (p *Pair) UnmarshalJSON(b []byte) error {
+	var v pairJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	value, err := decodeValue(v.Value, v.ValueEncoding)
+	if err != nil {
+		return err
+	}
+	v.Value = value
+	*p = Pair(v.pairFields)
+
+	return nil
+}
+
+// decodeValue returns the value sent in JSON with the given encoding.
+func decodeValue(value, encoding string) (string, error) {
+	switch encoding {
+	case "":
+		return value, nil
+	case valueEncodingBase64:
+		b, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return "", fmt.Errorf("invalid base64 value: %w", err)
+		}
+		return string(b), nil
+	}
+
+	return "", fmt.Errorf("unknown value encoding %q", encoding)
 }
 
 type LogApplier func(buf []byte, index uint64) interface{}
@@ -27,19 +115,38 @@ type LogApplier func(buf []byte, index uint64) interface{}
 type LogAppliers map[MessageType]LogApplier
 
 type taskvaultFSM struct {
-	store SyncraStorage
+	store   SyncraStorage
+	watches *watchHub
+
+	// persistedIndex is the index up to which a durable store already held
+	// the log when the agent started. Raft replays those entries, they are
+	// skipped rather than applied twice.
+	persistedIndex uint64
 
 	logger *zap.SugaredLogger
 }
 
-func newFSM(store SyncraStorage, logger *zap.SugaredLogger) *taskvaultFSM {
+func newFSM(store SyncraStorage, watches *watchHub, logger *zap.SugaredLogger) *taskvaultFSM {
 	return &taskvaultFSM{
-		store:  store,
-		logger: logger,
+		store:   store,
+		watches: watches,
+		logger:  logger,
 	}
 }
 
+// skipPersisted makes the FSM skip the log entries up to index, which the
+// store already holds. Their events were never published on this node, so
+// watches can not resume from before index.
+func (d *taskvaultFSM) skipPersisted(index uint64) {
+	d.persistedIndex = index
+	d.watches.compact(index)
+}
+
 func (d *taskvaultFSM) Apply(l *raft.Log) interface{} {
+	if l.Index <= d.persistedIndex {
+		return nil
+	}
+
 	buf := l.Data
 	msgType := MessageType(buf[0])
 
@@ -47,66 +154,397 @@ func (d *taskvaultFSM) Apply(l *raft.Log) interface{} {
 
 	switch msgType {
 	case AddPairType:
-		return d.applyAddPair(buf[1:])
+		return d.applyAddPair(buf[1:], l.Index)
 	case DeletePairType:
-		return d.applyDeletePair(buf[1:])
+		return d.applyDeletePair(buf[1:], l.Index)
 	case UpdatePairType:
-		return d.applyUpdatePair(buf[1:])
+		return d.applyUpdatePair(buf[1:], l.Index)
+	case TxnType:
+		return d.applyTxn(buf[1:], l.Index)
+	case ACLBootstrapType:
+		return d.applyACLToken(buf[1:], l.Index, d.store.ACLBootstrap)
+	case ACLTokenSetType:
+		return d.applyACLToken(buf[1:], l.Index, d.store.ACLSetToken)
+	case ACLTokenDeleteType:
+		return d.applyACLDelete(buf[1:], l.Index, d.store.ACLDeleteToken)
+	case ACLPolicySetType:
+		return d.applyACLPolicy(buf[1:], l.Index)
+	case ACLPolicyDeleteType:
+		return d.applyACLDelete(buf[1:], l.Index, d.store.ACLDeletePolicy)
+	case SessionCreateType:
+		return d.applySessionCreate(buf[1:], l.Index)
+	case SessionRenewType:
+		return d.applySessionRenew(buf[1:], l.Index)
+	case SessionDestroyType:
+		return d.applySessionDestroy(buf[1:], l.Index)
+	case QueueEnqueueType:
+		return d.applyQueueEnqueue(buf[1:], l.Index)
+	case QueueClaimType:
+		return d.applyQueueClaim(buf[1:], l.Index)
+	case QueueAckType:
+		return d.applyQueueAck(buf[1:], l.Index)
+	case QueueNackType:
+		return d.applyQueueNack(buf[1:], l.Index)
+	case QueueDeleteType:
+		return d.applyQueueDelete(buf[1:], l.Index)
+	case JobSetType:
+		return d.applyJobSet(buf[1:], l.Index)
+	case JobDeleteType:
+		return d.applyJobDelete(buf[1:], l.Index)
+	case JobRunType:
+		return d.applyJobRun(buf[1:], l.Index)
+	case IncrType:
+		return d.applyIncr(buf[1:], l.Index)
 	}
 
 	return nil
 }
 
-func (d *taskvaultFSM) applyAddPair(buf []byte) interface{} {
+func (d *taskvaultFSM) applyAddPair(buf []byte, index uint64) interface{} {
 	var cvr types.CreateValueRequest
 	if err := proto.Unmarshal(buf, &cvr); err != nil {
 		return err
 	}
 
-	err := d.store.SetValue(cvr.Key, cvr.Value)
+	pair, err := d.store.SetValue(cvr.Key, string(cvr.Value), WriteOptions{
+		Index:     index,
+		CAS:       cvr.Cas,
+		ExpiresAt: cvr.ExpiresAt,
+		Acquire:   cvr.Acquire,
+		Release:   cvr.Release,
+		Meta:      keyMetaFromProto(cvr.Meta),
+	})
 	if err != nil {
 		return err
 	}
+	d.watches.publish(index, WatchEvent{Type: WatchPut, Index: index, Pair: *pair})
 
-	return nil
+	return pair
 }
 
-func (d *taskvaultFSM) applyDeletePair(buf []byte) interface{} {
+func (d *taskvaultFSM) applyIncr(buf []byte, index uint64) interface{} {
+	var req types.IncrRequest
+	if err := proto.Unmarshal(buf, &req); err != nil {
+		return err
+	}
+
+	pair, err := d.store.IncrValue(req.Key, IncrOptions{
+		Delta:     req.Delta,
+		Min:       req.Min,
+		Max:       req.Max,
+		ExpiresAt: req.ExpiresAt,
+		Now:       req.Now,
+		Index:     index,
+	})
+	if err != nil {
+		return err
+	}
+	d.watches.publish(index, WatchEvent{Type: WatchPut, Index: index, Pair: *pair})
+
+	return pair
+}
+
+func (d *taskvaultFSM) applyDeletePair(buf []byte, index uint64) interface{} {
 	var dpr types.DeleteValueRequest
 
 	if err := proto.Unmarshal(buf, &dpr); err != nil {
 		return err
 	}
 
-	err := d.store.DeleteValue(dpr.Key)
+	err := d.store.DeleteValue(dpr.Key, WriteOptions{
+		Index: index,
+		CAS:   dpr.Cas,
+	})
 	if err != nil {
 		return err
 	}
+	d.watches.publish(index, WatchEvent{
+		Type:  WatchDelete,
+		Index: index,
+		Pair:  Pair{Key: dpr.Key, ModifyIndex: index},
+	})
 
 	return nil
 }
 
-func (d *taskvaultFSM) applyUpdatePair(buf []byte) interface{} {
+func (d *taskvaultFSM) applyUpdatePair(buf []byte, index uint64) interface{} {
 	var uvr types.UpdateValueRequest
 	if err := proto.Unmarshal(buf, &uvr); err != nil {
 		return err
 	}
 
-	err := d.store.UpdateValue(uvr.Key, uvr.Value)
+	pair, err := d.store.UpdateValue(uvr.Key, string(uvr.Value), WriteOptions{
+		Index:     index,
+		CAS:       uvr.Cas,
+		ExpiresAt: uvr.ExpiresAt,
+		Acquire:   uvr.Acquire,
+		Release:   uvr.Release,
+		Meta:      keyMetaFromProto(uvr.Meta),
+	})
+	if err != nil {
+		return err
+	}
+	d.watches.publish(index, WatchEvent{Type: WatchPut, Index: index, Pair: *pair})
+
+	return pair
+}
+
+func (d *taskvaultFSM) applyTxn(buf []byte, index uint64) interface{} {
+	var tr types.TxnRequest
+	if err := proto.Unmarshal(buf, &tr); err != nil {
+		return err
+	}
+
+	results, err := d.store.Txn(txnOpsFromProto(tr.Ops), index)
+	if err != nil {
+		return err
+	}
+
+	var events []WatchEvent
+	for i, op := range tr.Ops {
+		switch {
+		case op.Type == types.TxnOp_SET:
+			events = append(events, WatchEvent{
+				Type:  WatchPut,
+				Index: index,
+				Pair:  results[i],
+			})
+		case op.Type == types.TxnOp_DELETE && results[i].Key != "":
+			events = append(events, WatchEvent{
+				Type:  WatchDelete,
+				Index: index,
+				Pair:  Pair{Key: op.Key, ModifyIndex: index},
+			})
+		}
+	}
+	d.watches.publish(index, events...)
+
+	return results
+}
+
+func (d *taskvaultFSM) applyACLToken(
+	buf []byte, index uint64, set func(*ACLToken, uint64) (*ACLToken, error),
+) interface{} {
+	var t types.ACLToken
+	if err := proto.Unmarshal(buf, &t); err != nil {
+		return err
+	}
+
+	token, err := set(aclTokenFromProto(&t), index)
+	if err != nil {
+		return err
+	}
+
+	return token
+}
+
+func (d *taskvaultFSM) applyACLPolicy(buf []byte, index uint64) interface{} {
+	var p types.ACLPolicy
+	if err := proto.Unmarshal(buf, &p); err != nil {
+		return err
+	}
+
+	policy, err := d.store.ACLSetPolicy(aclPolicyFromProto(&p), index)
+	if err != nil {
+		return err
+	}
+
+	return policy
+}
+
+func (d *taskvaultFSM) applyACLDelete(
+	buf []byte, index uint64, del func(string, uint64) error,
+) interface{} {
+	var req types.ACLDeleteRequest
+	if err := proto.Unmarshal(buf, &req); err != nil {
+		return err
+	}
+
+	return del(req.Id, index)
+}
+
+func (d *taskvaultFSM) applySessionCreate(buf []byte, index uint64) interface{} {
+	var s types.Session
+	if err := proto.Unmarshal(buf, &s); err != nil {
+		return err
+	}
+
+	session, err := d.store.SessionCreate(sessionFromProto(&s), index)
+	if err != nil {
+		return err
+	}
+
+	return session
+}
+
+func (d *taskvaultFSM) applySessionRenew(buf []byte, index uint64) interface{} {
+	var req types.SessionRequest
+	if err := proto.Unmarshal(buf, &req); err != nil {
+		return err
+	}
+
+	session, err := d.store.SessionRenew(req.Id, req.ExpiresAt, index)
+	if err != nil {
+		return err
+	}
+
+	return session
+}
+
+// applySessionDestroy destroys a session, watchers see its locked keys
+// being released or deleted.
+func (d *taskvaultFSM) applySessionDestroy(buf []byte, index uint64) interface{} {
+	var req types.SessionRequest
+	if err := proto.Unmarshal(buf, &req); err != nil {
+		return err
+	}
+
+	released, deleted, err := d.store.SessionDestroy(req.Id, req.Cas, index)
 	if err != nil {
 		return err
 	}
 
+	var events []WatchEvent
+	for _, pair := range released {
+		events = append(events, WatchEvent{Type: WatchPut, Index: index, Pair: pair})
+	}
+	for _, pair := range deleted {
+		events = append(events, WatchEvent{Type: WatchDelete, Index: index, Pair: pair})
+	}
+	d.watches.publish(index, events...)
+
 	return nil
 }
 
+func (d *taskvaultFSM) applyQueueEnqueue(buf []byte, index uint64) interface{} {
+	var t types.Task
+	if err := proto.Unmarshal(buf, &t); err != nil {
+		return err
+	}
+
+	task, err := d.store.QueueEnqueue(taskFromProto(&t), index)
+	if err != nil {
+		return err
+	}
+
+	return task
+}
+
+// applyQueueClaim claims the next task ready at the time the leader put in
+// the request, so every replica picks the same one. The result is a nil
+// *Task when the queue had nothing ready.
+func (d *taskvaultFSM) applyQueueClaim(buf []byte, index uint64) interface{} {
+	var req types.QueueClaimRequest
+	if err := proto.Unmarshal(buf, &req); err != nil {
+		return err
+	}
+
+	visibleUntil := req.Now + int64(time.Duration(req.VisibilityTimeout)*time.Second)
+	task, err := d.store.QueueClaim(req.Queue, req.Now, visibleUntil, req.ClaimId, index)
+	if err != nil {
+		return err
+	}
+
+	return task
+}
+
+func (d *taskvaultFSM) applyQueueAck(buf []byte, index uint64) interface{} {
+	var req types.QueueTaskRequest
+	if err := proto.Unmarshal(buf, &req); err != nil {
+		return err
+	}
+
+	return d.store.QueueAck(req.Queue, req.Id, req.ClaimId, index)
+}
+
+func (d *taskvaultFSM) applyQueueNack(buf []byte, index uint64) interface{} {
+	var req types.QueueTaskRequest
+	if err := proto.Unmarshal(buf, &req); err != nil {
+		return err
+	}
+
+	task, err := d.store.QueueNack(req.Queue, req.Id, req.ClaimId, req.VisibleAt, req.Error, index)
+	if err != nil {
+		return err
+	}
+
+	return task
+}
+
+func (d *taskvaultFSM) applyQueueDelete(buf []byte, index uint64) interface{} {
+	var req types.QueueTaskRequest
+	if err := proto.Unmarshal(buf, &req); err != nil {
+		return err
+	}
+
+	return d.store.QueueDelete(req.Queue, req.Id, index)
+}
+
+func (d *taskvaultFSM) applyJobSet(buf []byte, index uint64) interface{} {
+	var j types.Job
+	if err := proto.Unmarshal(buf, &j); err != nil {
+		return err
+	}
+
+	job, err := d.store.JobSet(jobFromProto(&j), index)
+	if err != nil {
+		return err
+	}
+
+	return job
+}
+
+func (d *taskvaultFSM) applyJobDelete(buf []byte, index uint64) interface{} {
+	var req types.JobRequest
+	if err := proto.Unmarshal(buf, &req); err != nil {
+		return err
+	}
+
+	return d.store.JobDelete(req.Name, index)
+}
+
+// applyJobRun records a run of a job, watchers see the key it set.
+func (d *taskvaultFSM) applyJobRun(buf []byte, index uint64) interface{} {
+	var req types.JobRunRequest
+	if err := proto.Unmarshal(buf, &req); err != nil {
+		return err
+	}
+
+	job, pair, err := d.store.JobRun(
+		req.Name, req.ScheduledAt, req.NextRunAt, req.Skip, req.TaskId, req.Now, index,
+	)
+	if err != nil {
+		return err
+	}
+
+	if pair != nil {
+		d.watches.publish(index, WatchEvent{Type: WatchPut, Index: index, Pair: *pair})
+	}
+
+	return job
+}
+
 func (d *taskvaultFSM) Snapshot() (raft.FSMSnapshot, error) {
 	return &taskvaultSnapshot{store: d.store}, nil
 }
 
 func (d *taskvaultFSM) Restore(r io.ReadCloser) error {
 	defer r.Close()
-	return d.store.Restore(r)
+	if err := d.store.Restore(r); err != nil {
+		return err
+	}
+	d.watches.reset()
+
+	// The store is copied when the snapshot is persisted, not when it is
+	// taken, so it can hold entries after the snapshot index. Raft replays
+	// those, they are skipped like the entries of a durable store.
+	applied, err := d.store.AppliedIndex()
+	if err != nil {
+		return err
+	}
+	d.skipPersisted(applied)
+
+	return nil
 }
 
 type taskvaultSnapshot struct {
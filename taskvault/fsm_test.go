@@ -0,0 +1,134 @@
+package taskvault
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/danluki/taskvault/pkg/types"
+	"github.com/hashicorp/raft"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestFSM(t *testing.T) *taskvaultFSM {
+	return newFSM(newTestStore(t), newWatchHub(), zap.NewNop().Sugar())
+}
+
+func applyCommand(t *testing.T, fsm *taskvaultFSM, index uint64, mt MessageType, msg any) interface{} {
+	buf, err := Encode(mt, msg)
+	require.NoError(t, err)
+
+	return fsm.Apply(&raft.Log{Index: index, Data: buf})
+}
+
+func TestFSM_NotifiesWatchers(t *testing.T) {
+	fsm := newTestFSM(t)
+
+	key := fsm.watches.Watch("config/app", false)
+	defer fsm.watches.Stop(key)
+	prefix := fsm.watches.Watch("config/", true)
+	defer fsm.watches.Stop(prefix)
+	other := fsm.watches.Watch("other", false)
+	defer fsm.watches.Stop(other)
+
+	res := applyCommand(t, fsm, 7, AddPairType, &types.CreateValueRequest{
+		Key:   "config/app",
+		Value: []byte("v1"),
+	})
+	require.IsType(t, &Pair{}, res)
+
+	assert.Equal(t, uint64(7), <-key.ch)
+	assert.Equal(t, uint64(7), <-prefix.ch)
+	assert.Empty(t, other.ch)
+
+	applyCommand(t, fsm, 8, DeletePairType, &types.DeleteValueRequest{Key: "config/db"})
+	assert.Empty(t, prefix.ch, "failed writes must not notify")
+
+	applyCommand(t, fsm, 9, TxnType, &types.TxnRequest{Ops: []*types.TxnOp{
+		{Type: types.TxnOp_SET, Key: "config/db", Value: []byte("v1")},
+	}})
+	applyCommand(t, fsm, 10, UpdatePairType, &types.UpdateValueRequest{
+		Key:   "config/db",
+		Value: []byte("v2"),
+	})
+	assert.Equal(t, uint64(10), <-prefix.ch, "pending notifications are merged")
+}
+
+func TestFSM_SkipsPersistedEntries(t *testing.T) {
+	fsm := newTestFSM(t)
+	fsm.persistedIndex = 5
+
+	res := applyCommand(t, fsm, 5, AddPairType, &types.CreateValueRequest{
+		Key:   "key",
+		Value: []byte("replayed"),
+	})
+	assert.Nil(t, res)
+	_, err := fsm.store.GetValue("key")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+
+	res = applyCommand(t, fsm, 6, AddPairType, &types.CreateValueRequest{
+		Key:   "key",
+		Value: []byte("new"),
+	})
+	require.IsType(t, &Pair{}, res)
+}
+
+func TestFSM_ResumeWatchAfterRestart(t *testing.T) {
+	fsm := newTestFSM(t)
+	_, err := fsm.store.SetValue("key", "persisted", WriteOptions{Index: 5})
+	require.NoError(t, err)
+
+	// A restart replays the log the store already holds.
+	fsm.skipPersisted(5)
+	applyCommand(t, fsm, 5, AddPairType, &types.CreateValueRequest{
+		Key:   "key",
+		Value: []byte("persisted"),
+	})
+	applyCommand(t, fsm, 6, UpdatePairType, &types.UpdateValueRequest{
+		Key:   "key",
+		Value: []byte("new"),
+	})
+
+	_, _, err = fsm.watches.Subscribe("key", false, 4, 6)
+	assert.ErrorIs(t, err, ErrCompacted, "the change at 5 was not published")
+
+	sub, backlog, err := fsm.watches.Subscribe("key", false, 5, 6)
+	require.NoError(t, err)
+	defer fsm.watches.Unsubscribe(sub)
+	require.Len(t, backlog, 1)
+	assert.Equal(t, uint64(6), backlog[0].Index)
+}
+
+// bufferSink is a raft.SnapshotSink writing to memory.
+type bufferSink struct {
+	bytes.Buffer
+}
+
+func (s *bufferSink) ID() string    { return "test" }
+func (s *bufferSink) Cancel() error { return nil }
+func (s *bufferSink) Close() error  { return nil }
+
+func TestFSM_RestoreSkipsPersistedEntries(t *testing.T) {
+	fsm := newTestFSM(t)
+	incr := &types.IncrRequest{Key: "n", Delta: 1}
+
+	applyCommand(t, fsm, 1, IncrType, incr)
+	snap, err := fsm.Snapshot()
+	require.NoError(t, err)
+	// Raft keeps applying while the snapshot is persisted.
+	applyCommand(t, fsm, 2, IncrType, incr)
+	sink := &bufferSink{}
+	require.NoError(t, snap.Persist(sink))
+
+	restored := newTestFSM(t)
+	require.NoError(t, restored.Restore(io.NopCloser(&sink.Buffer)))
+	// Raft replays the log after the snapshot index.
+	assert.Nil(t, applyCommand(t, restored, 2, IncrType, incr))
+	applyCommand(t, restored, 3, IncrType, incr)
+
+	p, err := restored.store.GetValue("n")
+	require.NoError(t, err)
+	assert.Equal(t, "3", p.Value)
+}
@@ -3,16 +3,23 @@ package taskvault
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"net"
+	"path"
+	"strconv"
 	"time"
 
 	"github.com/armon/go-metrics"
 	types2 "github.com/danluki/taskvault/pkg/types"
+	"github.com/hashicorp/go-uuid"
 	"github.com/hashicorp/raft"
 	"github.com/hashicorp/serf/serf"
 	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
@@ -20,11 +27,13 @@ import (
 type TaskvaultGRPCServer interface {
 	types2.TaskvaultServer
 	Serve(net.Listener) error
+	Shutdown(ctx context.Context)
 }
 
 type GRPCServer struct {
 	types2.TaskvaultServer
 	agent  *Agent
+	server *grpc.Server
 	logger *zap.SugaredLogger
 }
 
@@ -36,14 +45,151 @@ func NewGRPCServer(agent *Agent, logger *zap.SugaredLogger) TaskvaultGRPCServer
 }
 
 func (grpcs *GRPCServer) Serve(lis net.Listener) error {
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(grpcs.aclUnaryInterceptor),
+		grpc.StreamInterceptor(grpcs.aclStreamInterceptor),
+	)
 	types2.RegisterTaskvaultServer(grpcServer, grpcs)
 
+	grpcs.server = grpcServer
+
 	go grpcServer.Serve(lis)
 
 	return nil
 }
 
+// Shutdown stops accepting calls and waits for the pending ones until ctx
+// is done, the remaining calls are cancelled then.
+func (grpcs *GRPCServer) Shutdown(ctx context.Context) {
+	if grpcs.server == nil {
+		return
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		grpcs.server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		grpcs.server.Stop()
+	}
+}
+
+// aclUnaryInterceptor resolves the ACL token of a call and rejects it when
+// the token lacks the permissions of the method.
+func (g *GRPCServer) aclUnaryInterceptor(
+	ctx context.Context,
+	req any,
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (any, error) {
+	ctx, err := g.authorize(ctx, info.FullMethod, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+func (g *GRPCServer) aclStreamInterceptor(
+	srv any,
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	ctx, err := g.authorize(ss.Context(), info.FullMethod, nil)
+	if err != nil {
+		return err
+	}
+
+	return handler(srv, &aclServerStream{ServerStream: ss, ctx: ctx})
+}
+
+// aclServerStream hands the authorized context to stream handlers.
+type aclServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *aclServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (g *GRPCServer) authorize(
+	ctx context.Context, method string, req any,
+) (context.Context, error) {
+	token := aclTokenFromMetadata(ctx)
+	authz, err := g.agent.resolveToken(token)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	if !rpcAllowed(authz, method, req) {
+		return nil, toStatusError(ErrPermissionDenied)
+	}
+
+	return withACL(ctx, token, authz), nil
+}
+
+// rpcAllowed checks the permissions a call needs up front. Reads are
+// checked key by key where they are served. Queues follow the key rules of
+// queues/<name>, jobs the rules of the key or queue they write to. Sessions
+// are tied to nodes and need agent write. Servers call RaftGetConfiguration
+// and RaftStats on each other with the agent token. Calls not listed need a
+// management token.
+func rpcAllowed(authz *aclAuthorizer, method string, req any) bool {
+	switch r := req.(type) {
+	case *types2.CreateValueRequest:
+		return authz.KeyWrite(r.Key)
+	case *types2.UpdateValueRequest:
+		return authz.KeyWrite(r.Key)
+	case *types2.DeleteValueRequest:
+		return authz.KeyWrite(r.Key)
+	case *types2.IncrRequest:
+		return authz.KeyWrite(r.Key)
+	case *types2.TxnRequest:
+		for _, op := range r.Ops {
+			switch op.Type {
+			case types2.TxnOp_SET, types2.TxnOp_DELETE:
+				if !authz.KeyWrite(op.Key) {
+					return false
+				}
+			default:
+				if !authz.KeyRead(op.Key) {
+					return false
+				}
+			}
+		}
+		return true
+	case *types2.Task:
+		return authz.KeyWrite(queueACLKey(r.Queue))
+	case *types2.QueueClaimRequest:
+		return authz.KeyWrite(queueACLKey(r.Queue))
+	case *types2.QueueTaskRequest:
+		return authz.KeyWrite(queueACLKey(r.Queue))
+	case *types2.QueueListRequest:
+		return authz.KeyRead(queueACLKey(r.Queue))
+	case *types2.Job:
+		return authz.KeyWrite(jobFromProto(r).aclKey())
+	}
+
+	switch path.Base(method) {
+	case "GetValue", "GetAllPairs", "Watch", "JobDelete":
+		// Checked where they are served.
+		return true
+	case "RaftGetConfiguration", "RaftStats":
+		return authz.OperatorRead()
+	case "Leave", "RaftRemovePeerByID", "RaftTransferLeader":
+		return authz.OperatorWrite()
+	case "SessionCreate", "SessionRenew", "SessionDestroy":
+		return authz.AgentWrite()
+	}
+
+	return authz.Management()
+}
+
 func Encode(t MessageType, msg any) ([]byte, error) {
 	var buf bytes.Buffer
 	buf.WriteByte(uint8(t))
@@ -61,23 +207,51 @@ func (g *GRPCServer) CreateValue(
 ) (*types2.CreateValueResponse, error) {
 	defer metrics.MeasureSince([]string{"grpc", "create_value"}, time.Now())
 
-	if err := g.agent.applySetPair(
-		&types2.Pair{
-			Key:   req.Key,
-			Value: req.Value,
-		},
-	); err != nil {
-		return nil, err
+	if isReservedKey(req.Key) {
+		return nil, status.Error(codes.InvalidArgument, ErrReservedKey.Error())
 	}
+	if req.Acquire != "" && req.Release != "" {
+		return nil, status.Error(codes.InvalidArgument, errAcquireAndRelease.Error())
+	}
+	if err := g.agent.checkSize(req.Key, req.Value); err != nil {
+		return nil, toStatusError(err)
+	}
+	if err := validateKeyMeta(req.Meta); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	var resp *types2.CreateValueResponse
+	if done, err := g.forward(ctx, "CreateValue", func(ctx context.Context, c types2.TaskvaultClient) error {
+		var err error
+		resp, err = c.CreateValue(ctx, req)
+		return err
+	}); done {
+		return resp, err
+	}
+
+	req.ExpiresAt = expiresAt(req.Ttl)
 
-	err := g.agent.Store.SetValue(req.Key, req.Value)
+	res, err := g.agent.raftApply(AddPairType, req)
 	if err != nil {
-		return nil, err
+		return nil, toStatusError(err)
+	}
+
+	pair, ok := res.(*Pair)
+	if !ok {
+		return nil, fmt.Errorf(
+			"grpc: Error wrong response from apply in CreateValue: %v", res,
+		)
 	}
 
 	return &types2.CreateValueResponse{
-		Key:   req.Key,
-		Value: req.Value,
+		Key:         pair.Key,
+		Value:       []byte(pair.Value),
+		CreateIndex: pair.CreateIndex,
+		ModifyIndex: pair.ModifyIndex,
+		Ttl:         pair.TTL,
+		Session:     pair.Session,
+		LockIndex:   pair.LockIndex,
+		Meta:        pair.Meta.proto(),
 	}, nil
 }
 
@@ -87,54 +261,67 @@ func (g *GRPCServer) DeleteValue(
 ) (*types2.DeleteValueResponse, error) {
 	defer metrics.MeasureSince([]string{"grpc", "delete_value"}, time.Now())
 
-	cmd, err := Encode(DeletePairType, &req)
-	if err != nil {
-		return nil, err
+	if isReservedKey(req.Key) {
+		return nil, status.Error(codes.InvalidArgument, ErrReservedKey.Error())
 	}
 
-	af := g.agent.raft.Apply(cmd, raftTimeout)
-	if err := af.Error(); err != nil {
-		return nil, err
+	var resp *types2.DeleteValueResponse
+	if done, err := g.forward(ctx, "DeleteValue", func(ctx context.Context, c types2.TaskvaultClient) error {
+		var err error
+		resp, err = c.DeleteValue(ctx, req)
+		return err
+	}); done {
+		return resp, err
 	}
 
-	err = g.agent.Store.DeleteValue(req.Key)
-	if err != nil {
-		return nil, err
+	if _, err := g.agent.raftApply(DeletePairType, req); err != nil {
+		return nil, toStatusError(err)
 	}
 
-	res := af.Response()
-	resm, ok := res.(*types2.DeleteValueResponse)
-	if !ok {
-		return nil, fmt.Errorf(
-			"grpc: Error wrong response from apply in DeleteValue: %v", res,
-		)
-	}
-
-	return resm, nil
+	return &types2.DeleteValueResponse{
+		Key: req.Key,
+	}, nil
 }
 
 func (g *GRPCServer) GetAllPairs(
 	ctx context.Context,
-	req *emptypb.Empty,
+	req *types2.GetAllPairsRequest,
 ) (*types2.GetAllPairsResponse, error) {
 	defer metrics.MeasureSince([]string{"grpc", "get_all_pairs"}, time.Now())
 	g.logger.Debug("grpc: Received GetAllPairs")
 
-	pairs, err := g.agent.Store.GetAllValues()
+	pairs, total, meta, err := g.agent.listValues(ctx, ListOptions{
+		Prefix:    req.Prefix,
+		StartKey:  req.StartKey,
+		EndKey:    req.EndKey,
+		KeysOnly:  req.KeysOnly,
+		Separator: req.Separator,
+		Offset:    int(req.Offset),
+		Limit:     int(req.Limit),
+	}, req.Consistency)
 	if err != nil {
-		return nil, err
+		return nil, toStatusError(err)
 	}
 
 	p := make([]*types2.Pair, len(pairs))
 	for i, pair := range pairs {
 		p[i] = &types2.Pair{
-			Key:   pair.Key,
-			Value: pair.Value,
+			Key:         pair.Key,
+			Value:       []byte(pair.Value),
+			CreateIndex: pair.CreateIndex,
+			ModifyIndex: pair.ModifyIndex,
+			Ttl:         pair.TTL,
+			Session:     pair.Session,
+			LockIndex:   pair.LockIndex,
+			Meta:        pair.Meta.proto(),
 		}
 	}
 
 	return &types2.GetAllPairsResponse{
-		Pairs: p,
+		Pairs:       p,
+		Total:       int64(total),
+		KnownLeader: meta.KnownLeader,
+		LastContact: meta.LastContact.Milliseconds(),
 	}, nil
 }
 
@@ -144,20 +331,34 @@ func (g *GRPCServer) GetValue(
 ) (*types2.GetValueResponse, error) {
 	defer metrics.MeasureSince([]string{"grpc", "get_value"}, time.Now())
 
-	pair, err := g.agent.Store.GetValue(req.Key)
+	pair, meta, err := g.agent.getValue(ctx, req.Key, req.Consistency)
 	if err != nil {
-		return nil, err
+		return nil, toStatusError(err)
 	}
 
 	return &types2.GetValueResponse{
-		Value: pair,
+		Value:       []byte(pair.Value),
+		CreateIndex: pair.CreateIndex,
+		ModifyIndex: pair.ModifyIndex,
+		Ttl:         pair.TTL,
+		Session:     pair.Session,
+		LockIndex:   pair.LockIndex,
+		Meta:        pair.Meta.proto(),
+		KnownLeader: meta.KnownLeader,
+		LastContact: meta.LastContact.Milliseconds(),
 	}, nil
 }
 
 func (g *GRPCServer) Leave(
 	ctx context.Context, req *emptypb.Empty,
 ) (*emptypb.Empty, error) {
-	return req, g.agent.Stop()
+	if err := g.agent.Leave(); err != nil {
+		return nil, err
+	}
+
+	// The shutdown waits for this call to return.
+	go g.agent.Shutdown()
+	return req, nil
 }
 
 func (g *GRPCServer) RaftGetConfiguration(
@@ -210,12 +411,909 @@ func (g *GRPCServer) RaftRemovePeerByID(
 	ctx context.Context,
 	req *types2.RaftRemovePeerByIDRequest,
 ) (*emptypb.Empty, error) {
-	panic("unimplemeneted")
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "peer id is required")
+	}
+
+	if done, err := g.forward(ctx, "RaftRemovePeerByID", func(ctx context.Context, c types2.TaskvaultClient) error {
+		_, err := c.RaftRemovePeerByID(ctx, req)
+		return err
+	}); done {
+		if err != nil {
+			return nil, err
+		}
+		return &emptypb.Empty{}, nil
+	}
+
+	if !g.agent.IsLeader() {
+		return nil, toStatusError(raft.ErrNotLeader)
+	}
+
+	if err := g.agent.removeRaftPeerByID(raft.ServerID(req.Id)); err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// RaftStats reports the Raft progress of this node, the leader polls it to
+// track the health of the servers.
+func (g *GRPCServer) RaftStats(
+	ctx context.Context,
+	req *emptypb.Empty,
+) (*types2.RaftStatsResponse, error) {
+	return localRaftStats(g.agent), nil
+}
+
+func (g *GRPCServer) RaftTransferLeader(
+	ctx context.Context,
+	req *types2.RaftTransferLeaderRequest,
+) (*types2.RaftTransferLeaderResponse, error) {
+	var resp *types2.RaftTransferLeaderResponse
+	if done, err := g.forward(ctx, "RaftTransferLeader", func(ctx context.Context, c types2.TaskvaultClient) error {
+		var err error
+		resp, err = c.RaftTransferLeader(ctx, req)
+		return err
+	}); done {
+		return resp, err
+	}
+
+	if !g.agent.IsLeader() {
+		return nil, toStatusError(raft.ErrNotLeader)
+	}
+
+	addr, id, err := g.agent.transferLeadership(raft.ServerID(req.Id))
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &types2.RaftTransferLeaderResponse{
+		Id:      string(id),
+		Address: string(addr),
+	}, nil
 }
 
 func (g *GRPCServer) UpdateValue(
 	ctx context.Context,
 	req *types2.UpdateValueRequest,
 ) (*types2.UpdateValueResponse, error) {
-	panic("unimplemented")
+	defer metrics.MeasureSince([]string{"grpc", "update_value"}, time.Now())
+
+	if isReservedKey(req.Key) {
+		return nil, status.Error(codes.InvalidArgument, ErrReservedKey.Error())
+	}
+	if req.Acquire != "" && req.Release != "" {
+		return nil, status.Error(codes.InvalidArgument, errAcquireAndRelease.Error())
+	}
+	if err := g.agent.checkSize(req.Key, req.Value); err != nil {
+		return nil, toStatusError(err)
+	}
+	if err := validateKeyMeta(req.Meta); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	var resp *types2.UpdateValueResponse
+	if done, err := g.forward(ctx, "UpdateValue", func(ctx context.Context, c types2.TaskvaultClient) error {
+		var err error
+		resp, err = c.UpdateValue(ctx, req)
+		return err
+	}); done {
+		return resp, err
+	}
+
+	req.ExpiresAt = expiresAt(req.Ttl)
+
+	res, err := g.agent.raftApply(UpdatePairType, req)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	pair, ok := res.(*Pair)
+	if !ok {
+		return nil, fmt.Errorf(
+			"grpc: Error wrong response from apply in UpdateValue: %v", res,
+		)
+	}
+
+	return &types2.UpdateValueResponse{
+		Key:         pair.Key,
+		Value:       []byte(pair.Value),
+		CreateIndex: pair.CreateIndex,
+		ModifyIndex: pair.ModifyIndex,
+		Ttl:         pair.TTL,
+		Session:     pair.Session,
+		LockIndex:   pair.LockIndex,
+		Meta:        pair.Meta.proto(),
+	}, nil
+}
+
+func (g *GRPCServer) Txn(
+	ctx context.Context,
+	req *types2.TxnRequest,
+) (*types2.TxnResponse, error) {
+	defer metrics.MeasureSince([]string{"grpc", "txn"}, time.Now())
+
+	if err := validateTxn(req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	for _, op := range req.Ops {
+		if op.Type != types2.TxnOp_SET {
+			continue
+		}
+		if err := g.agent.checkSize(op.Key, op.Value); err != nil {
+			return nil, toStatusError(err)
+		}
+	}
+
+	var resp *types2.TxnResponse
+	if done, err := g.forward(ctx, "Txn", func(ctx context.Context, c types2.TaskvaultClient) error {
+		var err error
+		resp, err = c.Txn(ctx, req)
+		return err
+	}); done {
+		return resp, err
+	}
+
+	for _, op := range req.Ops {
+		if op.Type == types2.TxnOp_SET {
+			op.ExpiresAt = expiresAt(op.Ttl)
+		}
+	}
+
+	res, err := g.agent.raftApply(TxnType, req)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	results, ok := res.([]Pair)
+	if !ok {
+		return nil, fmt.Errorf(
+			"grpc: Error wrong response from apply in Txn: %v", res,
+		)
+	}
+
+	resp = &types2.TxnResponse{Results: make([]*types2.Pair, len(results))}
+	for i, pair := range results {
+		resp.Results[i] = &types2.Pair{
+			Key:         pair.Key,
+			Value:       []byte(pair.Value),
+			CreateIndex: pair.CreateIndex,
+			ModifyIndex: pair.ModifyIndex,
+			Ttl:         pair.TTL,
+			Session:     pair.Session,
+			LockIndex:   pair.LockIndex,
+			Meta:        pair.Meta.proto(),
+		}
+	}
+
+	return resp, nil
+}
+
+// forward sends a write received by a follower to the leader and reports
+// whether it did. Writes forwarded by another node are always applied here,
+// if this node is no longer the leader they fail with raft.ErrNotLeader and
+// the sender retries them.
+func (g *GRPCServer) forward(
+	ctx context.Context,
+	method string,
+	call func(context.Context, types2.TaskvaultClient) error,
+) (bool, error) {
+	if g.agent.IsLeader() || isForwarded(ctx) {
+		return false, nil
+	}
+
+	return true, toStatusError(g.agent.requestClient(ctx).CallLeader(ctx, method, call))
+}
+
+func (g *GRPCServer) Watch(
+	req *types2.WatchRequest,
+	stream types2.Taskvault_WatchServer,
+) error {
+	authz := aclFromContext(stream.Context())
+	if !req.Prefix && !authz.KeyRead(req.Key) {
+		return toStatusError(ErrPermissionDenied)
+	}
+
+	applied := g.agent.raft.AppliedIndex()
+	sub, backlog, err := g.agent.watches.Subscribe(
+		req.Key, req.Prefix, req.StartIndex, applied,
+	)
+	if err != nil {
+		if errors.Is(err, ErrCompacted) {
+			return status.Error(codes.OutOfRange, err.Error())
+		}
+		return err
+	}
+	defer g.agent.watches.Unsubscribe(sub)
+
+	last := req.StartIndex
+	if last == 0 {
+		last = applied
+	}
+	send := func(ev WatchEvent) error {
+		last = ev.Index
+		if !authz.KeyRead(ev.Pair.Key) {
+			return nil
+		}
+		return stream.Send(toWatchEvent(ev))
+	}
+
+	for _, ev := range backlog {
+		if err := send(ev); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case ev, ok := <-sub.ch:
+			if !ok {
+				return status.Errorf(
+					codes.Aborted,
+					"watch interrupted, resume from index %d", last,
+				)
+			}
+			if err := send(ev); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+func toWatchEvent(ev WatchEvent) *types2.WatchEvent {
+	t := types2.WatchEvent_PUT
+	if ev.Type == WatchDelete {
+		t = types2.WatchEvent_DELETE
+	}
+
+	return &types2.WatchEvent{
+		Type:        t,
+		Key:         ev.Pair.Key,
+		Value:       []byte(ev.Pair.Value),
+		CreateIndex: ev.Pair.CreateIndex,
+		ModifyIndex: ev.Index,
+	}
+}
+
+// SessionCreate creates a session tied to the node given in the request,
+// or to the node that received it.
+func (g *GRPCServer) SessionCreate(
+	ctx context.Context, req *types2.Session,
+) (*types2.Session, error) {
+	defer metrics.MeasureSince([]string{"grpc", "session_create"}, time.Now())
+
+	if req.Node == "" {
+		req.Node = g.agent.config.NodeName
+	}
+
+	var resp *types2.Session
+	if done, err := g.forward(ctx, "SessionCreate", func(ctx context.Context, c types2.TaskvaultClient) error {
+		var err error
+		resp, err = c.SessionCreate(ctx, req)
+		return err
+	}); done {
+		return resp, err
+	}
+
+	session := sessionFromProto(req)
+	if err := session.validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if !g.agent.memberAlive(session.Node) {
+		return nil, status.Errorf(codes.InvalidArgument, "node %q is not alive", session.Node)
+	}
+
+	id, err := uuid.GenerateUUID()
+	if err != nil {
+		return nil, err
+	}
+	session.ID = id
+	session.ExpiresAt = expiresAt(session.TTL)
+
+	res, err := g.agent.raftApply(SessionCreateType, session.proto())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	session, ok := res.(*Session)
+	if !ok {
+		return nil, fmt.Errorf(
+			"grpc: Error wrong response from apply in SessionCreate: %v", res,
+		)
+	}
+
+	return session.proto(), nil
+}
+
+// SessionRenew restarts the TTL of a session.
+func (g *GRPCServer) SessionRenew(
+	ctx context.Context, req *types2.SessionRequest,
+) (*types2.Session, error) {
+	defer metrics.MeasureSince([]string{"grpc", "session_renew"}, time.Now())
+
+	var resp *types2.Session
+	if done, err := g.forward(ctx, "SessionRenew", func(ctx context.Context, c types2.TaskvaultClient) error {
+		var err error
+		resp, err = c.SessionRenew(ctx, req)
+		return err
+	}); done {
+		return resp, err
+	}
+
+	session, err := g.agent.Store.SessionGet(req.Id)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	res, err := g.agent.raftApply(SessionRenewType, &types2.SessionRequest{
+		Id:        req.Id,
+		ExpiresAt: expiresAt(session.TTL),
+	})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	session, ok := res.(*Session)
+	if !ok {
+		return nil, fmt.Errorf(
+			"grpc: Error wrong response from apply in SessionRenew: %v", res,
+		)
+	}
+
+	return session.proto(), nil
+}
+
+// SessionDestroy destroys a session and frees its locks.
+func (g *GRPCServer) SessionDestroy(
+	ctx context.Context, req *types2.SessionRequest,
+) (*emptypb.Empty, error) {
+	defer metrics.MeasureSince([]string{"grpc", "session_destroy"}, time.Now())
+
+	if done, err := g.forward(ctx, "SessionDestroy", func(ctx context.Context, c types2.TaskvaultClient) error {
+		_, err := c.SessionDestroy(ctx, req)
+		return err
+	}); done {
+		return &emptypb.Empty{}, err
+	}
+
+	if _, err := g.agent.raftApply(SessionDestroyType, req); err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// Incr adds the delta of the request to a counter and returns its new
+// value.
+func (g *GRPCServer) Incr(ctx context.Context, req *types2.IncrRequest) (*types2.IncrResponse, error) {
+	defer metrics.MeasureSince([]string{"grpc", "incr"}, time.Now())
+
+	if isReservedKey(req.Key) {
+		return nil, status.Error(codes.InvalidArgument, ErrReservedKey.Error())
+	}
+	if req.Min != nil && req.Max != nil && *req.Min > *req.Max {
+		return nil, status.Errorf(codes.InvalidArgument, "min %d is above max %d", *req.Min, *req.Max)
+	}
+	if err := g.agent.checkSize(req.Key, nil); err != nil {
+		return nil, toStatusError(err)
+	}
+
+	var resp *types2.IncrResponse
+	if done, err := g.forward(ctx, "Incr", func(ctx context.Context, c types2.TaskvaultClient) error {
+		var err error
+		resp, err = c.Incr(ctx, req)
+		return err
+	}); done {
+		return resp, err
+	}
+
+	req.ExpiresAt = expiresAt(req.Ttl)
+	req.Now = time.Now().UnixNano()
+
+	res, err := g.agent.raftApply(IncrType, req)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	pair, ok := res.(*Pair)
+	if !ok {
+		return nil, fmt.Errorf(
+			"grpc: Error wrong response from apply in Incr: %v", res,
+		)
+	}
+	value, err := strconv.ParseInt(pair.Value, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types2.IncrResponse{
+		Key:         pair.Key,
+		Value:       value,
+		CreateIndex: pair.CreateIndex,
+		ModifyIndex: pair.ModifyIndex,
+		Ttl:         pair.TTL,
+	}, nil
+}
+
+// QueueEnqueue adds a task to a queue. It can be claimed once its delay has
+// passed.
+func (g *GRPCServer) QueueEnqueue(ctx context.Context, req *types2.Task) (*types2.Task, error) {
+	defer metrics.MeasureSince([]string{"grpc", "queue_enqueue"}, time.Now())
+
+	if err := validateQueueName(req.Queue); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if req.Delay < 0 {
+		return nil, status.Error(codes.InvalidArgument, "delay must not be negative")
+	}
+	if err := g.agent.checkSize("", []byte(req.Payload)); err != nil {
+		return nil, toStatusError(err)
+	}
+
+	var resp *types2.Task
+	if done, err := g.forward(ctx, "QueueEnqueue", func(ctx context.Context, c types2.TaskvaultClient) error {
+		var err error
+		resp, err = c.QueueEnqueue(ctx, req)
+		return err
+	}); done {
+		return resp, err
+	}
+
+	id, err := uuid.GenerateUUID()
+	if err != nil {
+		return nil, err
+	}
+	task := taskFromProto(req)
+	task.ID = id
+	task.VisibleAt = afterSeconds(req.Delay)
+	if task.MaxAttempts == 0 {
+		task.MaxAttempts = defaultMaxAttempts
+	}
+
+	res, err := g.agent.raftApply(QueueEnqueueType, task.proto())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	task, ok := res.(*Task)
+	if !ok {
+		return nil, fmt.Errorf(
+			"grpc: Error wrong response from apply in QueueEnqueue: %v", res,
+		)
+	}
+
+	return task.proto(), nil
+}
+
+// QueueClaim leases the next ready task of a queue to the caller for the
+// visibility timeout. The response holds no task when none is ready.
+func (g *GRPCServer) QueueClaim(
+	ctx context.Context, req *types2.QueueClaimRequest,
+) (*types2.QueueClaimResponse, error) {
+	defer metrics.MeasureSince([]string{"grpc", "queue_claim"}, time.Now())
+
+	if err := validateQueueName(req.Queue); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	var resp *types2.QueueClaimResponse
+	if done, err := g.forward(ctx, "QueueClaim", func(ctx context.Context, c types2.TaskvaultClient) error {
+		var err error
+		resp, err = c.QueueClaim(ctx, req)
+		return err
+	}); done {
+		return resp, err
+	}
+
+	claimID, err := uuid.GenerateUUID()
+	if err != nil {
+		return nil, err
+	}
+	visibility := req.VisibilityTimeout
+	if visibility <= 0 {
+		visibility = int64(defaultVisibilityTimeout / time.Second)
+	}
+
+	res, err := g.agent.raftApply(QueueClaimType, &types2.QueueClaimRequest{
+		Queue:             req.Queue,
+		VisibilityTimeout: visibility,
+		Now:               time.Now().UnixNano(),
+		ClaimId:           claimID,
+	})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	task, ok := res.(*Task)
+	if !ok {
+		return nil, fmt.Errorf(
+			"grpc: Error wrong response from apply in QueueClaim: %v", res,
+		)
+	}
+	if task == nil {
+		return &types2.QueueClaimResponse{}, nil
+	}
+
+	return &types2.QueueClaimResponse{Task: task.proto()}, nil
+}
+
+// QueueAck completes a claimed task and removes it from its queue.
+func (g *GRPCServer) QueueAck(
+	ctx context.Context, req *types2.QueueTaskRequest,
+) (*emptypb.Empty, error) {
+	defer metrics.MeasureSince([]string{"grpc", "queue_ack"}, time.Now())
+
+	if done, err := g.forward(ctx, "QueueAck", func(ctx context.Context, c types2.TaskvaultClient) error {
+		_, err := c.QueueAck(ctx, req)
+		return err
+	}); done {
+		return &emptypb.Empty{}, err
+	}
+
+	if _, err := g.agent.raftApply(QueueAckType, req); err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// QueueNack hands a claimed task back to be retried after the delay, or
+// dead-letters it when it is out of attempts.
+func (g *GRPCServer) QueueNack(
+	ctx context.Context, req *types2.QueueTaskRequest,
+) (*types2.Task, error) {
+	defer metrics.MeasureSince([]string{"grpc", "queue_nack"}, time.Now())
+
+	if req.Delay < 0 {
+		return nil, status.Error(codes.InvalidArgument, "delay must not be negative")
+	}
+
+	var resp *types2.Task
+	if done, err := g.forward(ctx, "QueueNack", func(ctx context.Context, c types2.TaskvaultClient) error {
+		var err error
+		resp, err = c.QueueNack(ctx, req)
+		return err
+	}); done {
+		return resp, err
+	}
+
+	res, err := g.agent.raftApply(QueueNackType, &types2.QueueTaskRequest{
+		Queue:     req.Queue,
+		Id:        req.Id,
+		ClaimId:   req.ClaimId,
+		Error:     req.Error,
+		VisibleAt: afterSeconds(req.Delay),
+	})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	task, ok := res.(*Task)
+	if !ok {
+		return nil, fmt.Errorf(
+			"grpc: Error wrong response from apply in QueueNack: %v", res,
+		)
+	}
+
+	return task.proto(), nil
+}
+
+// QueueDelete removes a task whatever its state, mostly to purge dead
+// letters.
+func (g *GRPCServer) QueueDelete(
+	ctx context.Context, req *types2.QueueTaskRequest,
+) (*emptypb.Empty, error) {
+	defer metrics.MeasureSince([]string{"grpc", "queue_delete"}, time.Now())
+
+	if done, err := g.forward(ctx, "QueueDelete", func(ctx context.Context, c types2.TaskvaultClient) error {
+		_, err := c.QueueDelete(ctx, req)
+		return err
+	}); done {
+		return &emptypb.Empty{}, err
+	}
+
+	if _, err := g.agent.raftApply(QueueDeleteType, req); err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// QueueList returns the tasks of a queue from the local store.
+func (g *GRPCServer) QueueList(
+	ctx context.Context, req *types2.QueueListRequest,
+) (*types2.QueueListResponse, error) {
+	defer metrics.MeasureSince([]string{"grpc", "queue_list"}, time.Now())
+
+	if err := validateQueueName(req.Queue); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	tasks, err := g.agent.Store.QueueTasks(req.Queue, req.State)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	resp := &types2.QueueListResponse{}
+	for _, task := range tasks {
+		resp.Tasks = append(resp.Tasks, task.proto())
+	}
+
+	return resp, nil
+}
+
+// JobSet creates or replaces a job, its next run is computed from now.
+func (g *GRPCServer) JobSet(ctx context.Context, req *types2.Job) (*types2.Job, error) {
+	defer metrics.MeasureSince([]string{"grpc", "job_set"}, time.Now())
+
+	job := jobFromProto(req)
+	if err := job.validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := g.agent.checkSize(job.Key, []byte(job.Payload)); err != nil {
+		return nil, toStatusError(err)
+	}
+
+	var resp *types2.Job
+	if done, err := g.forward(ctx, "JobSet", func(ctx context.Context, c types2.TaskvaultClient) error {
+		var err error
+		resp, err = c.JobSet(ctx, req)
+		return err
+	}); done {
+		return resp, err
+	}
+
+	if err := g.jobWriteAllowed(ctx, job.Name); err != nil {
+		return nil, err
+	}
+
+	schedule, err := parseCron(job.Schedule)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	job.NextRunAt = nextRun(schedule, time.Now())
+	if job.NextRunAt == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "schedule %q never fires", job.Schedule)
+	}
+
+	res, err := g.agent.raftApply(JobSetType, job.proto())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	job, ok := res.(*Job)
+	if !ok {
+		return nil, fmt.Errorf(
+			"grpc: Error wrong response from apply in JobSet: %v", res,
+		)
+	}
+
+	return job.proto(), nil
+}
+
+// JobDelete removes a job, runs in flight are not affected.
+func (g *GRPCServer) JobDelete(
+	ctx context.Context, req *types2.JobRequest,
+) (*emptypb.Empty, error) {
+	defer metrics.MeasureSince([]string{"grpc", "job_delete"}, time.Now())
+
+	if done, err := g.forward(ctx, "JobDelete", func(ctx context.Context, c types2.TaskvaultClient) error {
+		_, err := c.JobDelete(ctx, req)
+		return err
+	}); done {
+		return &emptypb.Empty{}, err
+	}
+
+	if err := g.jobWriteAllowed(ctx, req.Name); err != nil {
+		return nil, err
+	}
+
+	if _, err := g.agent.raftApply(JobDeleteType, req); err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// jobWriteAllowed checks that the caller may write to what the stored job
+// of that name targets, so a job can only be replaced or deleted by whoever
+// could have created it.
+func (g *GRPCServer) jobWriteAllowed(ctx context.Context, name string) error {
+	job, err := g.agent.Store.JobGet(name)
+	if errors.Is(err, ErrJobNotFound) {
+		return nil
+	}
+	if err != nil {
+		return toStatusError(err)
+	}
+	if !aclFromContext(ctx).KeyWrite(job.aclKey()) {
+		return toStatusError(ErrPermissionDenied)
+	}
+
+	return nil
+}
+
+// expiresAt turns a TTL in seconds into an absolute deadline. It is computed
+// once before the command enters the log so all replicas agree on it.
+func expiresAt(ttl int64) int64 {
+	if ttl <= 0 {
+		return 0
+	}
+
+	return time.Now().Add(time.Duration(ttl) * time.Second).UnixNano()
+}
+
+const (
+	casConflictReason = "CAS_CONFLICT"
+	txnFailedReason   = "TXN_FAILED"
+	notLeaderReason   = "NOT_LEADER"
+
+	lockConflictReason    = "LOCK_CONFLICT"
+	sessionNotFoundReason = "SESSION_NOT_FOUND"
+
+	taskNotFoundReason   = "TASK_NOT_FOUND"
+	taskNotClaimedReason = "TASK_NOT_CLAIMED"
+	jobNotFoundReason    = "JOB_NOT_FOUND"
+	notANumberReason     = "NOT_A_NUMBER"
+	outOfBoundsReason    = "OUT_OF_BOUNDS"
+	tooLargeReason       = "TOO_LARGE"
+)
+
+// reasonError returns a status error carrying reason, so the client can
+// tell the error apart from others with the same code.
+func reasonError(code codes.Code, err error, reason string) error {
+	st, detailErr := status.New(code, err.Error()).WithDetails(
+		&errdetails.ErrorInfo{Reason: reason},
+	)
+	if detailErr != nil {
+		return status.Error(code, err.Error())
+	}
+
+	return st.Err()
+}
+
+// toStatusError maps store errors to gRPC status errors so that clients can
+// tell them apart from transport failures.
+func toStatusError(err error) error {
+	var casErr *CASError
+	var txnErr *TxnError
+	var sizeErr *SizeError
+	switch {
+	case errors.As(err, &txnErr):
+		st, detailErr := status.New(codes.Aborted, err.Error()).WithDetails(
+			&errdetails.ErrorInfo{
+				Reason: txnFailedReason,
+				Metadata: map[string]string{
+					"op_index": strconv.Itoa(txnErr.OpIndex),
+					"error":    txnErr.Err.Error(),
+				},
+			},
+		)
+		if detailErr != nil {
+			return status.Error(codes.Aborted, err.Error())
+		}
+		return st.Err()
+	case errors.As(err, &sizeErr):
+		st, detailErr := status.New(codes.InvalidArgument, err.Error()).WithDetails(
+			&errdetails.ErrorInfo{
+				Reason: tooLargeReason,
+				Metadata: map[string]string{
+					"field": sizeErr.Field,
+					"size":  strconv.Itoa(sizeErr.Size),
+					"limit": strconv.Itoa(sizeErr.Limit),
+				},
+			},
+		)
+		if detailErr != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		return st.Err()
+	case errors.Is(err, ErrKeyNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, ErrTaskNotFound):
+		return reasonError(codes.NotFound, err, taskNotFoundReason)
+	case errors.Is(err, ErrTaskNotClaimed):
+		return reasonError(codes.FailedPrecondition, err, taskNotClaimedReason)
+	case errors.Is(err, ErrJobNotFound):
+		return reasonError(codes.NotFound, err, jobNotFoundReason)
+	case errors.Is(err, ErrNotANumber):
+		return reasonError(codes.FailedPrecondition, err, notANumberReason)
+	case errors.Is(err, ErrOutOfBounds):
+		return reasonError(codes.FailedPrecondition, err, outOfBoundsReason)
+	case errors.Is(err, ErrLockConflict):
+		return reasonError(codes.FailedPrecondition, err, lockConflictReason)
+	case errors.Is(err, ErrSessionNotFound):
+		return reasonError(codes.FailedPrecondition, err, sessionNotFoundReason)
+	case errors.Is(err, ErrPermissionDenied):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, ErrUnknownPeer):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, ErrLastVoter), errors.Is(err, ErrNoTransferTarget),
+		errors.Is(err, raft.ErrLeadershipTransferInProgress):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, raft.ErrNotLeader):
+		st, detailErr := status.New(codes.Unavailable, err.Error()).WithDetails(
+			&errdetails.ErrorInfo{Reason: notLeaderReason},
+		)
+		if detailErr != nil {
+			return status.Error(codes.Unavailable, err.Error())
+		}
+		return st.Err()
+	case isUnavailable(err):
+		return status.Error(codes.Unavailable, err.Error())
+	case errors.As(err, &casErr):
+		st, detailErr := status.New(codes.FailedPrecondition, err.Error()).WithDetails(
+			&errdetails.ErrorInfo{
+				Reason: casConflictReason,
+				Metadata: map[string]string{
+					"key":          casErr.Key,
+					"modify_index": strconv.FormatUint(casErr.ModifyIndex, 10),
+				},
+			},
+		)
+		if detailErr != nil {
+			return status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return st.Err()
+	}
+
+	return err
+}
+
+// fromStatusError is the client side counterpart of toStatusError.
+func fromStatusError(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	switch st.Code() {
+	case codes.PermissionDenied:
+		return ErrPermissionDenied
+	case codes.NotFound, codes.InvalidArgument, codes.FailedPrecondition, codes.Aborted, codes.Unavailable:
+		for _, d := range st.Details() {
+			info, ok := d.(*errdetails.ErrorInfo)
+			if !ok {
+				continue
+			}
+			switch info.Reason {
+			case casConflictReason:
+				index, _ := strconv.ParseUint(info.Metadata["modify_index"], 10, 64)
+				return &CASError{Key: info.Metadata["key"], ModifyIndex: index}
+			case txnFailedReason:
+				opIndex, _ := strconv.Atoi(info.Metadata["op_index"])
+				return &TxnError{
+					OpIndex: opIndex,
+					Err:     errors.New(info.Metadata["error"]),
+				}
+			case notLeaderReason:
+				return raft.ErrNotLeader
+			case lockConflictReason:
+				return ErrLockConflict
+			case sessionNotFoundReason:
+				return ErrSessionNotFound
+			case taskNotFoundReason:
+				return ErrTaskNotFound
+			case taskNotClaimedReason:
+				return ErrTaskNotClaimed
+			case jobNotFoundReason:
+				return ErrJobNotFound
+			case notANumberReason:
+				return ErrNotANumber
+			case outOfBoundsReason:
+				return ErrOutOfBounds
+			case tooLargeReason:
+				size, _ := strconv.Atoi(info.Metadata["size"])
+				limit, _ := strconv.Atoi(info.Metadata["limit"])
+				return &SizeError{Field: info.Metadata["field"], Size: size, Limit: limit}
+			}
+		}
+		if st.Code() == codes.NotFound {
+			return ErrKeyNotFound
+		}
+	}
+
+	return err
 }
@@ -2,31 +2,79 @@ package taskvault
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strconv"
 	"time"
 
 	types2 "github.com/danluki/taskvault/pkg/types"
 	metrics "github.com/hashicorp/go-metrics"
+	"github.com/hashicorp/raft"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
+const (
+	// writeHoldTimeout bounds how long a write waits for a leader to be
+	// elected before it fails.
+	writeHoldTimeout   = 5 * time.Second
+	writeRetryInterval = 250 * time.Millisecond
+	// leaderConnectTimeout bounds how long a call waits for the connection
+	// to the leader before the leader is looked up again.
+	leaderConnectTimeout = time.Second
+	// leaderCallTimeout bounds one call to the leader, which gives up
+	// applying a write after raftTimeout.
+	leaderCallTimeout = leaderConnectTimeout + raftTimeout
+	// raftStatsTimeout bounds a health check, so one stuck server does not
+	// hold up autopilot.
+	raftStatsTimeout = time.Second
+)
+
+var errLeaderUnreachable = fmt.Errorf("leader unreachable: %w", ErrNoLeader)
+
 type TaskvaultGRPCClient interface {
 	Connect(string) (*grpc.ClientConn, error)
-	CreateValue(string, string) (*Pair, error)
-	UpdateValue(string, string) (*Pair, error)
-	GetValue(string, string) (*Pair, error)
+	CallLeader(context.Context, string, func(context.Context, types2.TaskvaultClient) error) error
+	CreateValue(*types2.CreateValueRequest) (*Pair, error)
+	UpdateValue(*types2.UpdateValueRequest) (*Pair, error)
+	GetValue(string, *types2.GetValueRequest) (*Pair, QueryMeta, error)
 	GetAllValues() ([]Pair, error)
-	DeleteValue(string) error
+	ListValues(string, *types2.GetAllPairsRequest) ([]Pair, int, QueryMeta, error)
+	DeleteValue(*types2.DeleteValueRequest) error
+	Incr(*types2.IncrRequest) (*Pair, error)
+	Txn(*types2.TxnRequest) (*types2.TxnResponse, error)
+	SessionCreate(*types2.Session) (*Session, error)
+	SessionRenew(string) (*Session, error)
+	SessionDestroy(string) error
+	QueueEnqueue(*types2.Task) (*Task, error)
+	QueueClaim(string, int64) (*Task, error)
+	QueueAck(string, string, string) error
+	QueueNack(*types2.QueueTaskRequest) (*Task, error)
+	QueueDelete(string, string) error
+	JobSet(*types2.Job) (*Job, error)
+	JobDelete(string) error
 	Leave(string) error
 	RaftGetConfiguration(string) (*types2.RaftGetConfigurationResponse, error)
 	RaftRemovePeerByID(string, string) error
+	RaftTransferLeader(string, string) (*types2.RaftTransferLeaderResponse, error)
+	RaftStats(string) (*types2.RaftStatsResponse, error)
+	WithToken(string) TaskvaultGRPCClient
+	WithContext(context.Context) TaskvaultGRPCClient
+	Shutdown()
 }
 
 type GRPCClient struct {
-	dialOpt []grpc.DialOption
-	agent   *Agent
-	logger  *zap.SugaredLogger
+	pool   *connPool
+	agent  *Agent
+	logger *zap.SugaredLogger
+	// token is the secret of the ACL token sent with every call.
+	token string
+	// ctx is the context of the request calls are made for, they are
+	// canceled with it.
+	ctx context.Context
 }
 
 func NewGRPCClient(
@@ -38,107 +86,442 @@ func NewGRPCClient(
 		dialOpt = grpc.WithInsecure()
 	}
 	return &GRPCClient{
-		dialOpt: []grpc.DialOption{
-			dialOpt,
-			grpc.WithBlock(),
-		},
+		pool:   newConnPool(dialOpt),
 		agent:  agent,
 		logger: logger,
 	}
 }
 
+// Connect returns the pooled connection to addr. It is shared with other
+// callers and must not be closed.
 func (grpcc *GRPCClient) Connect(addr string) (*grpc.ClientConn, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	return grpcc.pool.Get(addr)
+}
+
+// WithToken returns a client that sends the ACL token with the given
+// secret. It shares the connections of grpcc.
+func (grpcc *GRPCClient) WithToken(token string) TaskvaultGRPCClient {
+	c := *grpcc
+	c.token = token
+	return &c
+}
+
+// WithContext returns a client whose calls are made under ctx. It shares
+// the connections of grpcc.
+func (grpcc *GRPCClient) WithContext(ctx context.Context) TaskvaultGRPCClient {
+	c := *grpcc
+	c.ctx = ctx
+	return &c
+}
+
+// parent returns the context calls are made under.
+func (grpcc *GRPCClient) parent() context.Context {
+	if grpcc.ctx != nil {
+		return grpcc.ctx
+	}
+	return context.Background()
+}
+
+// outgoing returns ctx carrying the ACL token of the client.
+func (grpcc *GRPCClient) outgoing(ctx context.Context) context.Context {
+	if grpcc.token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, aclTokenKey, grpcc.token)
+	}
+	return ctx
+}
+
+// context returns the context of an outgoing call.
+func (grpcc *GRPCClient) context() context.Context {
+	return grpcc.outgoing(grpcc.parent())
+}
+
+func (grpcc *GRPCClient) forwardedContext(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(grpcc.outgoing(ctx), forwardedKey, "true")
+}
+
+// Shutdown closes the pooled connections.
+func (grpcc *GRPCClient) Shutdown() {
+	grpcc.pool.Shutdown()
+}
+
+// CallLeader runs call against the current leader under ctx, each attempt
+// bounded by leaderCallTimeout. While there is no leader, the leader can not
+// be reached or has just lost leadership, the leader is looked up again and
+// the call retried until writeHoldTimeout or until ctx is done. Calls are
+// only retried when they were never sent or were rejected with
+// raft.ErrNotLeader before reaching the log, so a write is never applied
+// twice.
+func (grpcc *GRPCClient) CallLeader(
+	ctx context.Context,
+	method string,
+	call func(context.Context, types2.TaskvaultClient) error,
+) error {
+	deadline := time.Now().Add(writeHoldTimeout)
+
+	for {
+		err := grpcc.callLeaderOnce(ctx, call)
+		if !errors.Is(err, ErrNoLeader) && !errors.Is(err, raft.ErrNotLeader) {
+			return err
+		}
+
+		if time.Now().Add(writeRetryInterval).After(deadline) {
+			grpcc.logger.Error("grpc: no leader to forward to",
+				zap.Error(err),
+				zap.String("method", method),
+			)
+			return err
+		}
+		metrics.IncrCounter([]string{"grpc", "forward", "retry"}, 1)
+		select {
+		case <-time.After(writeRetryInterval):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+func (grpcc *GRPCClient) callLeaderOnce(
+	ctx context.Context,
+	call func(context.Context, types2.TaskvaultClient) error,
+) error {
+	addr := grpcc.agent.raft.Leader()
+	if addr == "" {
+		return ErrNoLeader
+	}
+
+	conn, err := grpcc.Connect(string(addr))
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, leaderCallTimeout)
 	defer cancel()
-	conn, err := grpc.DialContext(ctx, addr, grpcc.dialOpt...)
+
+	if !waitReady(ctx, conn, leaderConnectTimeout) {
+		return errLeaderUnreachable
+	}
+
+	return fromStatusError(call(grpcc.forwardedContext(ctx), types2.NewTaskvaultClient(conn)))
+}
+
+func (grpcc *GRPCClient) CreateValue(req *types2.CreateValueRequest) (*Pair, error) {
+	defer metrics.MeasureSince([]string{"grpc", "create_value"}, time.Now())
+
+	var resp *types2.CreateValueResponse
+	err := grpcc.CallLeader(grpcc.parent(), "CreateValue", func(ctx context.Context, c types2.TaskvaultClient) error {
+		var err error
+		resp, err = c.CreateValue(ctx, req)
+		return err
+	})
+	if err != nil {
+		if !errors.Is(err, ErrCASConflict) {
+			grpcc.logger.Error("grpc: error calling",
+				zap.Error(err),
+				zap.String("method", "CreateValue"),
+			)
+		}
+		return nil, err
+	}
+
+	return &Pair{
+		Key:         resp.Key,
+		Value:       string(resp.Value),
+		CreateIndex: resp.CreateIndex,
+		ModifyIndex: resp.ModifyIndex,
+		TTL:         resp.Ttl,
+		Session:     resp.Session,
+		LockIndex:   resp.LockIndex,
+		Meta:        keyMetaFromProto(resp.Meta),
+	}, nil
+}
+
+func (grpcc *GRPCClient) DeleteValue(req *types2.DeleteValueRequest) error {
+	defer metrics.MeasureSince([]string{"grpc", "delete_value"}, time.Now())
+
+	return grpcc.CallLeader(grpcc.parent(), "DeleteValue", func(ctx context.Context, c types2.TaskvaultClient) error {
+		_, err := c.DeleteValue(ctx, req)
+		return err
+	})
+}
+
+func (grpcc *GRPCClient) Txn(req *types2.TxnRequest) (*types2.TxnResponse, error) {
+	defer metrics.MeasureSince([]string{"grpc", "txn"}, time.Now())
+
+	var resp *types2.TxnResponse
+	err := grpcc.CallLeader(grpcc.parent(), "Txn", func(ctx context.Context, c types2.TaskvaultClient) error {
+		var err error
+		resp, err = c.Txn(ctx, req)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
-	return conn, nil
+
+	return resp, nil
 }
 
-func (grpcc *GRPCClient) CreateValue(key string, value string) (*Pair, error) {
-	defer metrics.MeasureSince([]string{"grpc", "create_value"}, time.Now())
-	var conn *grpc.ClientConn
+func (grpcc *GRPCClient) SessionCreate(req *types2.Session) (*Session, error) {
+	defer metrics.MeasureSince([]string{"grpc", "session_create"}, time.Now())
 
-	addr := grpcc.agent.raft.Leader()
+	var resp *types2.Session
+	err := grpcc.CallLeader(grpcc.parent(), "SessionCreate", func(ctx context.Context, c types2.TaskvaultClient) error {
+		var err error
+		resp, err = c.SessionCreate(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	conn, err := grpcc.Connect(string(addr))
+	return sessionFromProto(resp), nil
+}
+
+func (grpcc *GRPCClient) SessionRenew(id string) (*Session, error) {
+	defer metrics.MeasureSince([]string{"grpc", "session_renew"}, time.Now())
+
+	var resp *types2.Session
+	err := grpcc.CallLeader(grpcc.parent(), "SessionRenew", func(ctx context.Context, c types2.TaskvaultClient) error {
+		var err error
+		resp, err = c.SessionRenew(ctx, &types2.SessionRequest{Id: id})
+		return err
+	})
 	if err != nil {
-		grpcc.logger.Error("grpc: error dialing",
-			zap.Error(err),
-			zap.String("method", "CreateValue"),
-		)
 		return nil, err
 	}
-	defer conn.Close()
 
-	d := types2.NewTaskvaultClient(conn)
-	resp, err := d.CreateValue(
-		context.Background(), &types2.CreateValueRequest{
-			Key:   key,
-			Value: value,
-		},
-	)
+	return sessionFromProto(resp), nil
+}
+
+func (grpcc *GRPCClient) SessionDestroy(id string) error {
+	defer metrics.MeasureSince([]string{"grpc", "session_destroy"}, time.Now())
+
+	return grpcc.CallLeader(grpcc.parent(), "SessionDestroy", func(ctx context.Context, c types2.TaskvaultClient) error {
+		_, err := c.SessionDestroy(ctx, &types2.SessionRequest{Id: id})
+		return err
+	})
+}
+
+func (grpcc *GRPCClient) Incr(req *types2.IncrRequest) (*Pair, error) {
+	defer metrics.MeasureSince([]string{"grpc", "incr"}, time.Now())
+
+	var resp *types2.IncrResponse
+	err := grpcc.CallLeader(grpcc.parent(), "Incr", func(ctx context.Context, c types2.TaskvaultClient) error {
+		var err error
+		resp, err = c.Incr(ctx, req)
+		return err
+	})
 	if err != nil {
-		grpcc.logger.Error("grpc: error calling",
-			zap.Error(err),
-			zap.String("method", "GetValue"),
-		)
 		return nil, err
 	}
 
 	return &Pair{
-		Key:   key,
-		Value: resp.Value,
+		Key:         resp.Key,
+		Value:       strconv.FormatInt(resp.Value, 10),
+		CreateIndex: resp.CreateIndex,
+		ModifyIndex: resp.ModifyIndex,
+		TTL:         resp.Ttl,
 	}, nil
 }
 
-func (grpcc *GRPCClient) DeleteValue(string) error {
-	panic("unimplemented")
+func (grpcc *GRPCClient) QueueEnqueue(req *types2.Task) (*Task, error) {
+	defer metrics.MeasureSince([]string{"grpc", "queue_enqueue"}, time.Now())
+
+	var resp *types2.Task
+	err := grpcc.CallLeader(grpcc.parent(), "QueueEnqueue", func(ctx context.Context, c types2.TaskvaultClient) error {
+		var err error
+		resp, err = c.QueueEnqueue(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return taskFromProto(resp), nil
+}
+
+// QueueClaim claims the next ready task of queue for visibility seconds. It
+// returns nil when no task is ready.
+func (grpcc *GRPCClient) QueueClaim(queue string, visibility int64) (*Task, error) {
+	defer metrics.MeasureSince([]string{"grpc", "queue_claim"}, time.Now())
+
+	var resp *types2.QueueClaimResponse
+	err := grpcc.CallLeader(grpcc.parent(), "QueueClaim", func(ctx context.Context, c types2.TaskvaultClient) error {
+		var err error
+		resp, err = c.QueueClaim(ctx, &types2.QueueClaimRequest{
+			Queue:             queue,
+			VisibilityTimeout: visibility,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Task == nil {
+		return nil, nil
+	}
+
+	return taskFromProto(resp.Task), nil
+}
+
+func (grpcc *GRPCClient) QueueAck(queue, id, claimID string) error {
+	defer metrics.MeasureSince([]string{"grpc", "queue_ack"}, time.Now())
+
+	return grpcc.CallLeader(grpcc.parent(), "QueueAck", func(ctx context.Context, c types2.TaskvaultClient) error {
+		_, err := c.QueueAck(ctx, &types2.QueueTaskRequest{Queue: queue, Id: id, ClaimId: claimID})
+		return err
+	})
+}
+
+func (grpcc *GRPCClient) QueueNack(req *types2.QueueTaskRequest) (*Task, error) {
+	defer metrics.MeasureSince([]string{"grpc", "queue_nack"}, time.Now())
+
+	var resp *types2.Task
+	err := grpcc.CallLeader(grpcc.parent(), "QueueNack", func(ctx context.Context, c types2.TaskvaultClient) error {
+		var err error
+		resp, err = c.QueueNack(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return taskFromProto(resp), nil
+}
+
+func (grpcc *GRPCClient) QueueDelete(queue, id string) error {
+	defer metrics.MeasureSince([]string{"grpc", "queue_delete"}, time.Now())
+
+	return grpcc.CallLeader(grpcc.parent(), "QueueDelete", func(ctx context.Context, c types2.TaskvaultClient) error {
+		_, err := c.QueueDelete(ctx, &types2.QueueTaskRequest{Queue: queue, Id: id})
+		return err
+	})
+}
+
+func (grpcc *GRPCClient) JobSet(req *types2.Job) (*Job, error) {
+	defer metrics.MeasureSince([]string{"grpc", "job_set"}, time.Now())
+
+	var resp *types2.Job
+	err := grpcc.CallLeader(grpcc.parent(), "JobSet", func(ctx context.Context, c types2.TaskvaultClient) error {
+		var err error
+		resp, err = c.JobSet(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return jobFromProto(resp), nil
+}
+
+func (grpcc *GRPCClient) JobDelete(name string) error {
+	defer metrics.MeasureSince([]string{"grpc", "job_delete"}, time.Now())
+
+	return grpcc.CallLeader(grpcc.parent(), "JobDelete", func(ctx context.Context, c types2.TaskvaultClient) error {
+		_, err := c.JobDelete(ctx, &types2.JobRequest{Name: name})
+		return err
+	})
 }
 
 func (grpcc *GRPCClient) GetAllValues() ([]Pair, error) {
 	panic("unimplemented")
 }
 
-func (grpcc *GRPCClient) GetValue(addr, key string) (*Pair, error) {
+// GetValue reads a key on the node at addr. Reads are marked as forwarded,
+// the node serves them itself or fails them if it is not the leader.
+func (grpcc *GRPCClient) GetValue(
+	addr string, req *types2.GetValueRequest,
+) (*Pair, QueryMeta, error) {
 	defer metrics.MeasureSince([]string{"grpc", "get_value"}, time.Now())
-	var conn *grpc.ClientConn
 
 	conn, err := grpcc.Connect(addr)
 	if err != nil {
-		return nil, err
+		return nil, QueryMeta{}, err
 	}
-	defer conn.Close()
 
 	d := types2.NewTaskvaultClient(conn)
-	resp, err := d.GetValue(
-		context.Background(), &types2.GetValueRequest{
-			Key: key,
-		},
-	)
+	resp, err := d.GetValue(grpcc.forwardedContext(grpcc.parent()), req)
 	if err != nil {
-		return nil, err
+		return nil, QueryMeta{}, fromStatusError(err)
 	}
 
 	return &Pair{
-		Key:   key,
-		Value: resp.Value,
+		Key:         req.Key,
+		Value:       string(resp.Value),
+		CreateIndex: resp.CreateIndex,
+		ModifyIndex: resp.ModifyIndex,
+		TTL:         resp.Ttl,
+		Session:     resp.Session,
+		LockIndex:   resp.LockIndex,
+		Meta:        keyMetaFromProto(resp.Meta),
+	}, QueryMeta{
+		KnownLeader: resp.KnownLeader,
+		LastContact: time.Duration(resp.LastContact) * time.Millisecond,
 	}, nil
 }
 
-func (grpcc *GRPCClient) Leave(addr string) error {
-	var conn *grpc.ClientConn
+// ListValues lists keys on the node at addr, see GetValue.
+func (grpcc *GRPCClient) ListValues(
+	addr string, req *types2.GetAllPairsRequest,
+) ([]Pair, int, QueryMeta, error) {
+	defer metrics.MeasureSince([]string{"grpc", "get_all_pairs"}, time.Now())
 
+	conn, err := grpcc.Connect(addr)
+	if err != nil {
+		return nil, 0, QueryMeta{}, err
+	}
+
+	d := types2.NewTaskvaultClient(conn)
+	resp, err := d.GetAllPairs(grpcc.forwardedContext(grpcc.parent()), req)
+	if err != nil {
+		return nil, 0, QueryMeta{}, fromStatusError(err)
+	}
+
+	pairs := make([]Pair, len(resp.Pairs))
+	for i, p := range resp.Pairs {
+		pairs[i] = Pair{
+			Key:         p.Key,
+			Value:       string(p.Value),
+			CreateIndex: p.CreateIndex,
+			ModifyIndex: p.ModifyIndex,
+			TTL:         p.Ttl,
+			Session:     p.Session,
+			LockIndex:   p.LockIndex,
+			Meta:        keyMetaFromProto(p.Meta),
+		}
+	}
+
+	return pairs, int(resp.Total), QueryMeta{
+		KnownLeader: resp.KnownLeader,
+		LastContact: time.Duration(resp.LastContact) * time.Millisecond,
+	}, nil
+}
+
+// waitReady waits until conn is connected, so that calls failing afterwards
+// are known to have been sent.
+func waitReady(ctx context.Context, conn *grpc.ClientConn, timeout time.Duration) bool {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	conn.Connect()
+	for {
+		state := conn.GetState()
+		if state == connectivity.Ready {
+			return true
+		}
+		if !conn.WaitForStateChange(ctx, state) {
+			return false
+		}
+	}
+}
+
+func (grpcc *GRPCClient) Leave(addr string) error {
 	conn, err := grpcc.Connect(addr)
 	if err != nil {
 		return err
 	}
-	defer conn.Close()
 
 	d := types2.NewTaskvaultClient(conn)
-	_, err = d.Leave(context.Background(), &emptypb.Empty{})
+	_, err = d.Leave(grpcc.context(), &emptypb.Empty{})
 	if err != nil {
 		return err
 	}
@@ -147,17 +530,14 @@ func (grpcc *GRPCClient) Leave(addr string) error {
 }
 
 func (grpcc *GRPCClient) RaftRemovePeerByID(addr string, peerID string) error {
-	var conn *grpc.ClientConn
-
 	conn, err := grpcc.Connect(addr)
 	if err != nil {
 		return err
 	}
-	defer conn.Close()
 
 	d := types2.NewTaskvaultClient(conn)
 	_, err = d.RaftRemovePeerByID(
-		context.Background(),
+		grpcc.context(),
 		&types2.RaftRemovePeerByIDRequest{Id: peerID},
 	)
 	if err != nil {
@@ -167,23 +547,79 @@ func (grpcc *GRPCClient) RaftRemovePeerByID(addr string, peerID string) error {
 	return nil
 }
 
-func (grpcc *GRPCClient) UpdateValue(string, string) (*Pair, error) {
-	panic("unimplemented")
+// RaftTransferLeader asks the node at addr to transfer leadership to the
+// server with the given id, or to any voter when id is empty. Followers
+// forward the request to the leader.
+func (grpcc *GRPCClient) RaftTransferLeader(
+	addr string, id string,
+) (*types2.RaftTransferLeaderResponse, error) {
+	conn, err := grpcc.Connect(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	d := types2.NewTaskvaultClient(conn)
+	return d.RaftTransferLeader(
+		grpcc.context(),
+		&types2.RaftTransferLeaderRequest{Id: id},
+	)
+}
+
+// RaftStats returns the Raft progress of the node at addr.
+func (grpcc *GRPCClient) RaftStats(addr string) (*types2.RaftStatsResponse, error) {
+	conn, err := grpcc.Connect(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(grpcc.context(), raftStatsTimeout)
+	defer cancel()
+
+	d := types2.NewTaskvaultClient(conn)
+	return d.RaftStats(ctx, &emptypb.Empty{})
+}
+
+func (grpcc *GRPCClient) UpdateValue(req *types2.UpdateValueRequest) (*Pair, error) {
+	defer metrics.MeasureSince([]string{"grpc", "update_value"}, time.Now())
+
+	var resp *types2.UpdateValueResponse
+	err := grpcc.CallLeader(grpcc.parent(), "UpdateValue", func(ctx context.Context, c types2.TaskvaultClient) error {
+		var err error
+		resp, err = c.UpdateValue(ctx, req)
+		return err
+	})
+	if err != nil {
+		if !errors.Is(err, ErrKeyNotFound) && !errors.Is(err, ErrCASConflict) {
+			grpcc.logger.Error("grpc: error calling",
+				zap.Error(err),
+				zap.String("method", "UpdateValue"),
+			)
+		}
+		return nil, err
+	}
+
+	return &Pair{
+		Key:         resp.Key,
+		Value:       string(resp.Value),
+		CreateIndex: resp.CreateIndex,
+		ModifyIndex: resp.ModifyIndex,
+		TTL:         resp.Ttl,
+		Session:     resp.Session,
+		LockIndex:   resp.LockIndex,
+		Meta:        keyMetaFromProto(resp.Meta),
+	}, nil
 }
 
 func (g *GRPCClient) RaftGetConfiguration(
 	addr string,
 ) (*types2.RaftGetConfigurationResponse, error) {
-	var conn *grpc.ClientConn
-
 	conn, err := g.Connect(addr)
 	if err != nil {
 		return nil, err
 	}
-	defer conn.Close()
 
 	d := types2.NewTaskvaultClient(conn)
-	res, err := d.RaftGetConfiguration(context.Background(), &emptypb.Empty{})
+	res, err := d.RaftGetConfiguration(g.context(), &emptypb.Empty{})
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,35 @@
+package taskvault
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	types2 "github.com/danluki/taskvault/pkg/types"
+	"github.com/hashicorp/raft"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestGRPCClient_CallLeaderContext(t *testing.T) {
+	conf := raft.DefaultConfig()
+	conf.LocalID = "n1"
+	_, transport := raft.NewInmemTransport("")
+	r, err := raft.NewRaft(conf, &raft.MockFSM{}, raft.NewInmemStore(), raft.NewInmemStore(),
+		raft.NewInmemSnapshotStore(), transport)
+	require.NoError(t, err)
+	defer r.Shutdown()
+
+	client := NewGRPCClient(nil, &Agent{raft: r}, zap.NewNop().Sugar())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err = client.CallLeader(ctx, "Test", func(context.Context, types2.TaskvaultClient) error {
+		return nil
+	})
+	assert.ErrorIs(t, err, ErrNoLeader)
+	assert.Less(t, time.Since(start), writeHoldTimeout, "gives up when the caller does")
+}
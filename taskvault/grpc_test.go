@@ -0,0 +1,79 @@
+package taskvault
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	types2 "github.com/danluki/taskvault/pkg/types"
+	"github.com/hashicorp/raft"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStatusErrorRoundTrip(t *testing.T) {
+	err := fromStatusError(toStatusError(&CASError{Key: "key", ModifyIndex: 7}))
+	var casErr *CASError
+	if assert.ErrorAs(t, err, &casErr) {
+		assert.Equal(t, uint64(7), casErr.ModifyIndex)
+	}
+
+	err = fromStatusError(toStatusError(&TxnError{OpIndex: 2, Err: ErrKeyNotFound}))
+	var txnErr *TxnError
+	if assert.ErrorAs(t, err, &txnErr) {
+		assert.Equal(t, 2, txnErr.OpIndex)
+	}
+
+	assert.ErrorIs(t, fromStatusError(toStatusError(ErrKeyNotFound)), ErrKeyNotFound)
+
+	err = fromStatusError(toStatusError(&SizeError{Field: "value", Size: 10, Limit: 4}))
+	var sizeErr *SizeError
+	if assert.ErrorAs(t, err, &sizeErr) {
+		assert.Equal(t, SizeError{Field: "value", Size: 10, Limit: 4}, *sizeErr)
+	}
+	assert.ErrorIs(t, err, ErrTooLarge)
+
+	st := toStatusError(raft.ErrNotLeader)
+	assert.Equal(t, codes.Unavailable, status.Code(st))
+	assert.ErrorIs(t, fromStatusError(st), raft.ErrNotLeader)
+
+	// Only writes rejected before reaching the log may be retried.
+	err = fromStatusError(toStatusError(raft.ErrLeadershipLost))
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.False(t, errors.Is(err, raft.ErrNotLeader))
+}
+
+func TestCheckSize(t *testing.T) {
+	a := &Agent{config: &Config{MaxKeySize: 3, MaxValueSize: 4}}
+
+	assert.NoError(t, a.checkSize("abc", []byte("abcd")))
+	assert.ErrorIs(t, a.checkSize("abcd", nil), ErrTooLarge)
+	var sizeErr *SizeError
+	if assert.ErrorAs(t, a.checkSize("a", []byte("abcde")), &sizeErr) {
+		assert.Equal(t, "value", sizeErr.Field)
+	}
+
+	// Zero lifts the limits.
+	a.config = &Config{}
+	assert.NoError(t, a.checkSize("abcd", []byte("abcde")))
+}
+
+func TestValidateKeyMeta(t *testing.T) {
+	assert.NoError(t, validateKeyMeta(nil))
+	assert.NoError(t, validateKeyMeta(&types2.KeyMeta{
+		ContentType: "application/json",
+		Labels:      map[string]string{"team": "infra"},
+	}))
+	assert.Error(t, validateKeyMeta(&types2.KeyMeta{Labels: map[string]string{"": "x"}}))
+	assert.Error(t, validateKeyMeta(&types2.KeyMeta{
+		ContentType: strings.Repeat("a", maxContentTypeSize+1),
+	}))
+
+	labels := map[string]string{}
+	for i := 0; i <= maxLabels; i++ {
+		labels[strconv.Itoa(i)] = "x"
+	}
+	assert.Error(t, validateKeyMeta(&types2.KeyMeta{Labels: labels}))
+}
@@ -0,0 +1,166 @@
+package taskvault
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/tidwall/buntdb"
+)
+
+// Entries and tasks are indexed by records in the reserved key space rather
+// than by BuntDB indexes, which would decode every value on every
+// comparison. The records are written and deleted in the transaction that
+// writes or deletes what they index, see indexEntry and indexTask.
+const (
+	// ttlPrefix records the keys that have a TTL by expiry. The expiry is
+	// zero padded so the records sort in time order and expired keys are
+	// found with a range scan.
+	ttlPrefix = reservedPrefix + "ttl/"
+	// lockPrefix records the keys locked by each session, so the locks of
+	// a destroyed session are found without scanning the store.
+	lockPrefix = reservedPrefix + "lock/"
+
+	// indexVersionKey holds the version of the records. Stores and
+	// snapshots of an older version have their records rebuilt.
+	indexVersionKey = reservedPrefix + "index_version"
+	indexVersion    = "3"
+)
+
+func ttlKey(expiresAt int64, key string) string {
+	return fmt.Sprintf("%s%020d/%s", ttlPrefix, expiresAt, key)
+}
+
+// ttlRecordKey returns the key a TTL record was written for.
+func ttlRecordKey(record string) string {
+	return record[len(ttlPrefix)+21:]
+}
+
+func lockKey(session, key string) string {
+	return lockPrefix + session + "/" + key
+}
+
+// indexEntry moves the records of key from its current entry to e. current
+// is nil for a new key, e is nil for a deleted one.
+func indexEntry(tx *buntdb.Tx, key string, current, e *entry) error {
+	var (
+		oldExpiry, newExpiry   int64
+		oldSession, newSession string
+	)
+	if current != nil {
+		oldExpiry, oldSession = current.ExpiresAt, current.Session
+	}
+	if e != nil {
+		newExpiry, newSession = e.ExpiresAt, e.Session
+	}
+
+	if oldExpiry != newExpiry {
+		if oldExpiry != 0 {
+			if err := deleteRecord(tx, ttlKey(oldExpiry, key)); err != nil {
+				return err
+			}
+		}
+		if newExpiry != 0 {
+			if _, _, err := tx.Set(ttlKey(newExpiry, key), "", nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	if oldSession != newSession {
+		if oldSession != "" {
+			if err := deleteRecord(tx, lockKey(oldSession, key)); err != nil {
+				return err
+			}
+		}
+		if newSession != "" {
+			if _, _, err := tx.Set(lockKey(newSession, key), "", nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// deleteEntry deletes key, whose entry is current, along with its records.
+func deleteEntry(tx *buntdb.Tx, key string, current *entry) error {
+	if _, err := tx.Delete(key); err != nil {
+		return err
+	}
+
+	return indexEntry(tx, key, current, nil)
+}
+
+func deleteRecord(tx *buntdb.Tx, key string) error {
+	if _, err := tx.Delete(key); err != nil && !errors.Is(err, buntdb.ErrNotFound) {
+		return err
+	}
+
+	return nil
+}
+
+// ensureIndex rebuilds the records of every entry and task unless they are
+// of the current version. Pending tasks are rebuilt as waiting, the next
+// claim finds them ready again, so a rebuild never changes what a write
+// does.
+func ensureIndex(tx *buntdb.Tx) error {
+	version, err := tx.Get(indexVersionKey)
+	if err != nil && !errors.Is(err, buntdb.ErrNotFound) {
+		return err
+	}
+	if version == indexVersion {
+		return nil
+	}
+
+	var stale []string
+	for _, prefix := range []string{
+		ttlPrefix, lockPrefix, queueWaitPrefix, queueReadyPrefix, queueLeasePrefix,
+	} {
+		err = tx.AscendRange("", prefix, prefixEnd(prefix), func(k, v string) bool {
+			stale = append(stale, k)
+			return true
+		})
+		if err != nil {
+			return err
+		}
+	}
+	for _, k := range stale {
+		if _, err := tx.Delete(k); err != nil {
+			return err
+		}
+	}
+
+	// Entries that fail to decode are left out, they have no expiry or lock
+	// to go by.
+	keys := map[string]*entry{}
+	err = tx.Ascend("", func(k, v string) bool {
+		if isReservedKey(k) {
+			return true
+		}
+		if e, err := decodeEntry(v); err == nil {
+			keys[k] = e
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	for k, e := range keys {
+		if err := indexEntry(tx, k, nil, e); err != nil {
+			return err
+		}
+	}
+
+	var tasks []*Task
+	if err := listTasks(tx, queuePrefix, func(t *Task) { tasks = append(tasks, t) }); err != nil {
+		return err
+	}
+	for _, t := range tasks {
+		if err := indexTask(tx, nil, t); err != nil {
+			return err
+		}
+	}
+
+	_, _, err = tx.Set(indexVersionKey, indexVersion, nil)
+	return err
+}
@@ -0,0 +1,153 @@
+package taskvault
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/danluki/taskvault/pkg/types"
+)
+
+// Catch-up policies, what the leader does with runs that were missed while
+// the cluster had no leader.
+const (
+	// JobCatchUpSkip drops missed runs.
+	JobCatchUpSkip = "skip"
+	// JobCatchUpOnce fires missed runs as a single run.
+	JobCatchUpOnce = "once"
+	// JobCatchUpAll fires every missed run.
+	JobCatchUpAll = "all"
+)
+
+const (
+	// jobMisfireThreshold is how late a run can be before it counts as
+	// missed and the catch-up policy applies.
+	jobMisfireThreshold = time.Minute
+	// maxJobRunsPerTick bounds the runs of one job fired in a single tick,
+	// the rest of an "all" catch-up follows on the next ticks.
+	maxJobRunsPerTick = 100
+)
+
+var (
+	ErrJobNotFound = errors.New("job not found")
+
+	// errJobRunRecorded is returned for a run that was recorded already,
+	// by this leader or a previous one.
+	errJobRunRecorded = errors.New("job run already recorded")
+)
+
+// Job sets a key or enqueues a task on a cron schedule. It is fired by the
+// leader, each run is recorded in the same log entry as its write so a run
+// happens exactly once, across leader changes too.
+type Job struct {
+	Name     string
+	Schedule string
+	Key      string `json:",omitempty"`
+	Queue    string `json:",omitempty"`
+	Payload  string
+	Priority int64 `json:",omitempty"`
+	CatchUp  string
+	// LastRunAt is in unix nanoseconds, 0 until the job first ran.
+	LastRunAt    int64
+	LastRunIndex uint64
+	// NextRunAt is the scheduled time of the next run in unix nanoseconds,
+	// 0 when the schedule does not fire anymore.
+	NextRunAt   int64
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+func jobFromProto(j *types.Job) *Job {
+	return &Job{
+		Name:         j.Name,
+		Schedule:     j.Schedule,
+		Key:          j.Key,
+		Queue:        j.Queue,
+		Payload:      j.Payload,
+		Priority:     j.Priority,
+		CatchUp:      j.CatchUp,
+		LastRunAt:    j.LastRunAt,
+		LastRunIndex: j.LastRunIndex,
+		NextRunAt:    j.NextRunAt,
+		CreateIndex:  j.CreateIndex,
+		ModifyIndex:  j.ModifyIndex,
+	}
+}
+
+func (j *Job) proto() *types.Job {
+	return &types.Job{
+		Name:         j.Name,
+		Schedule:     j.Schedule,
+		Key:          j.Key,
+		Queue:        j.Queue,
+		Payload:      j.Payload,
+		Priority:     j.Priority,
+		CatchUp:      j.CatchUp,
+		LastRunAt:    j.LastRunAt,
+		LastRunIndex: j.LastRunIndex,
+		NextRunAt:    j.NextRunAt,
+		CreateIndex:  j.CreateIndex,
+		ModifyIndex:  j.ModifyIndex,
+	}
+}
+
+// validate fills in the default catch-up policy and checks the job.
+func (j *Job) validate() error {
+	if j.Name == "" {
+		return errors.New("job name is required")
+	}
+	if strings.Contains(j.Name, "/") {
+		return fmt.Errorf("job name %q must not contain a slash", j.Name)
+	}
+	if _, err := parseCron(j.Schedule); err != nil {
+		return err
+	}
+
+	switch {
+	case j.Key != "" && j.Queue != "":
+		return errors.New("job can not target both a key and a queue")
+	case j.Key != "":
+		if isReservedKey(j.Key) {
+			return ErrReservedKey
+		}
+	case j.Queue != "":
+		if err := validateQueueName(j.Queue); err != nil {
+			return err
+		}
+	default:
+		return errors.New("job needs a key or a queue")
+	}
+
+	if j.CatchUp == "" {
+		j.CatchUp = JobCatchUpOnce
+	}
+	switch j.CatchUp {
+	case JobCatchUpSkip, JobCatchUpOnce, JobCatchUpAll:
+	default:
+		return fmt.Errorf("unknown catch-up policy %q", j.CatchUp)
+	}
+
+	return nil
+}
+
+// aclKey is the key whose rules govern access to the job, the key or queue
+// it writes to.
+func (j *Job) aclKey() string {
+	if j.Queue != "" {
+		return queueACLKey(j.Queue)
+	}
+
+	return j.Key
+}
+
+// nextRun returns the first run of s after t in unix nanoseconds, 0 when
+// there is none.
+func nextRun(s *cronSchedule, t time.Time) int64 {
+	next := s.next(t)
+	if next.IsZero() {
+		return 0
+	}
+
+	return next.UnixNano()
+}
@@ -0,0 +1,80 @@
+package taskvault
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// jobListHandler returns the jobs whose key or queue the token can read.
+func (h *HTTPTransport) jobListHandler(c *gin.Context) {
+	jobs, err := h.agent.Store.JobList()
+	if err != nil {
+		h.renderReadError(c, err)
+		return
+	}
+
+	authz := aclFromContext(c.Request.Context())
+	visible := []Job{}
+	for _, job := range jobs {
+		if authz.KeyRead(job.aclKey()) {
+			visible = append(visible, job)
+		}
+	}
+	renderJSON(c, http.StatusOK, visible)
+}
+
+func (h *HTTPTransport) jobGetHandler(c *gin.Context) {
+	job, err := h.agent.Store.JobGet(c.Param("name"))
+	if err != nil {
+		h.renderJobError(c, err)
+		return
+	}
+	if !aclFromContext(c.Request.Context()).KeyRead(job.aclKey()) {
+		_ = c.AbortWithError(http.StatusForbidden, ErrPermissionDenied)
+		return
+	}
+
+	renderJSON(c, http.StatusOK, job)
+}
+
+// jobSetHandler creates or replaces a job from the Schedule, Key or Queue,
+// Payload, Priority and CatchUp fields of the body. Schedules take the five
+// cron fields, optionally preceded by seconds, a descriptor like @hourly or
+// @every <duration>, and are evaluated in UTC.
+func (h *HTTPTransport) jobSetHandler(c *gin.Context) {
+	body := &Job{}
+	if err := c.ShouldBindJSON(body); err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+	body.Name = c.Param("name")
+
+	job, err := h.client(c).JobSet(body.proto())
+	if err != nil {
+		h.renderJobError(c, err)
+		return
+	}
+
+	setIndexHeader(c, job.ModifyIndex)
+	renderJSON(c, http.StatusOK, job)
+}
+
+func (h *HTTPTransport) jobDeleteHandler(c *gin.Context) {
+	if err := h.client(c).JobDelete(c.Param("name")); err != nil {
+		h.renderJobError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (h *HTTPTransport) renderJobError(c *gin.Context, err error) {
+	if errors.Is(err, ErrJobNotFound) {
+		_ = c.AbortWithError(http.StatusNotFound, err)
+		return
+	}
+
+	h.renderWriteError(c, err)
+}
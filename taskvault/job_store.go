@@ -0,0 +1,162 @@
+package taskvault
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/tidwall/buntdb"
+)
+
+// Jobs live in the reserved key space, their runs are recorded there in the
+// same transaction as the write they do.
+const jobPrefix = reservedPrefix + "job/"
+
+func getJob(tx *buntdb.Tx, name string) (*Job, error) {
+	var job Job
+	if err := getJSON(tx, jobPrefix+name, &job); err != nil {
+		if errors.Is(err, errRecordNotFound) {
+			return nil, ErrJobNotFound
+		}
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// JobSet creates or replaces a job. The runs of a replaced job are kept.
+func (s *Store) JobSet(job *Job, index uint64) (*Job, error) {
+	stored := *job
+	stored.CreateIndex, stored.ModifyIndex = index, index
+	stored.LastRunAt, stored.LastRunIndex = 0, 0
+
+	err := s.db.Update(func(tx *buntdb.Tx) error {
+		current, err := getJob(tx, stored.Name)
+		if err != nil && !errors.Is(err, ErrJobNotFound) {
+			return err
+		}
+		if current != nil {
+			stored.CreateIndex = current.CreateIndex
+			stored.LastRunAt = current.LastRunAt
+			stored.LastRunIndex = current.LastRunIndex
+		}
+
+		if err := setJSON(tx, jobPrefix+stored.Name, &stored); err != nil {
+			return err
+		}
+		return setAppliedIndex(tx, index)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &stored, nil
+}
+
+func (s *Store) JobDelete(name string, index uint64) error {
+	return s.db.Update(func(tx *buntdb.Tx) error {
+		if _, err := getJob(tx, name); err != nil {
+			return err
+		}
+
+		if _, err := tx.Delete(jobPrefix + name); err != nil {
+			return err
+		}
+		return setAppliedIndex(tx, index)
+	})
+}
+
+// JobRun records the run of a job scheduled at scheduledAt and moves the job
+// on to nextRunAt. Unless the run is skipped it sets the key of the job,
+// returned as a pair, or enqueues a task with taskID visible from now. A run
+// that is not the next one of the job fails with errJobRunRecorded, so each
+// run is fired once however many times the leader tries.
+func (s *Store) JobRun(
+	name string, scheduledAt, nextRunAt int64, skip bool, taskID string, now int64, index uint64,
+) (*Job, *Pair, error) {
+	var (
+		job  *Job
+		pair *Pair
+	)
+
+	err := s.db.Update(func(tx *buntdb.Tx) error {
+		var err error
+		job, err = getJob(tx, name)
+		if err != nil {
+			return err
+		}
+		if job.NextRunAt != scheduledAt {
+			return fmt.Errorf("%w: job %q is next due at %d", errJobRunRecorded, name, job.NextRunAt)
+		}
+
+		if !skip {
+			switch {
+			case job.Queue != "":
+				_, err = enqueueTask(tx, &Task{
+					ID:          taskID,
+					Queue:       job.Queue,
+					Payload:     job.Payload,
+					Priority:    job.Priority,
+					VisibleAt:   now,
+					MaxAttempts: defaultMaxAttempts,
+				}, index)
+				if err != nil {
+					return err
+				}
+			default:
+				current, err := getEntry(tx, job.Key)
+				if err != nil {
+					return err
+				}
+				e, err := putEntry(tx, job.Key, current, job.Payload, WriteOptions{Index: index})
+				if err != nil {
+					return err
+				}
+				p := e.pair(job.Key)
+				pair = &p
+			}
+			job.LastRunAt = now
+			job.LastRunIndex = index
+		}
+
+		job.NextRunAt = nextRunAt
+		job.ModifyIndex = index
+		if err := setJSON(tx, jobPrefix+name, job); err != nil {
+			return err
+		}
+		return setAppliedIndex(tx, index)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return job, pair, nil
+}
+
+func (s *Store) JobGet(name string) (*Job, error) {
+	var job *Job
+
+	err := s.db.View(func(tx *buntdb.Tx) error {
+		var err error
+		job, err = getJob(tx, name)
+		return err
+	})
+
+	return job, err
+}
+
+func (s *Store) JobList() ([]Job, error) {
+	var jobs []Job
+	err := s.db.View(func(tx *buntdb.Tx) error {
+		return listJSON(tx, jobPrefix, func(raw string) error {
+			var job Job
+			if err := json.Unmarshal([]byte(raw), &job); err != nil {
+				return err
+			}
+			jobs = append(jobs, job)
+			return nil
+		})
+	})
+
+	return jobs, err
+}
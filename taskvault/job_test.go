@@ -0,0 +1,87 @@
+package taskvault
+
+import (
+	"testing"
+	"time"
+
+	"github.com/danluki/taskvault/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJob_Validate(t *testing.T) {
+	job := &Job{Name: "j", Schedule: "@hourly", Key: "k"}
+	require.NoError(t, job.validate())
+	assert.Equal(t, JobCatchUpOnce, job.CatchUp)
+
+	assert.Error(t, (&Job{Name: "j", Schedule: "@hourly"}).validate())
+	assert.Error(t, (&Job{Name: "j", Schedule: "@hourly", Key: "k", Queue: "q"}).validate())
+	assert.Error(t, (&Job{Name: "j", Schedule: "never", Key: "k"}).validate())
+	assert.Error(t, (&Job{Name: "j", Schedule: "@hourly", Key: "k", CatchUp: "later"}).validate())
+	assert.ErrorIs(t, (&Job{Name: "j", Schedule: "@hourly", Key: reservedPrefix + "k"}).validate(), ErrReservedKey)
+}
+
+func TestStore_JobRun(t *testing.T) {
+	s := newTestStore(t)
+	now := time.Now().UnixNano()
+
+	_, err := s.JobSet(&Job{Name: "key", Schedule: "@hourly", Key: "out", Payload: "tick", NextRunAt: 100}, 1)
+	require.NoError(t, err)
+	_, err = s.JobSet(&Job{Name: "queue", Schedule: "@hourly", Queue: "jobs", Payload: "work", NextRunAt: 100}, 2)
+	require.NoError(t, err)
+
+	job, pair, err := s.JobRun("key", 100, 200, false, "", now, 3)
+	require.NoError(t, err)
+	require.NotNil(t, pair)
+	assert.Equal(t, "tick", pair.Value)
+	assert.Equal(t, now, job.LastRunAt)
+	assert.Equal(t, uint64(3), job.LastRunIndex)
+	assert.Equal(t, int64(200), job.NextRunAt)
+
+	// A run is only recorded once.
+	_, _, err = s.JobRun("key", 100, 200, false, "", now, 4)
+	assert.ErrorIs(t, err, errJobRunRecorded)
+
+	// Skipped runs move the job on without firing it.
+	job, pair, err = s.JobRun("key", 200, 300, true, "", now+1, 4)
+	require.NoError(t, err)
+	assert.Nil(t, pair)
+	assert.Equal(t, uint64(3), job.LastRunIndex)
+
+	_, pair, err = s.JobRun("queue", 100, 200, false, "task", now, 5)
+	require.NoError(t, err)
+	assert.Nil(t, pair)
+	tasks, err := s.QueueTasks("jobs", TaskPending)
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, "work", tasks[0].Payload)
+
+	// Replacing a job keeps its runs.
+	job, err = s.JobSet(&Job{Name: "key", Schedule: "@daily", Key: "out", NextRunAt: 400}, 6)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), job.LastRunIndex)
+	assert.Equal(t, uint64(1), job.CreateIndex)
+
+	require.NoError(t, s.JobDelete("key", 7))
+	assert.ErrorIs(t, s.JobDelete("key", 8), ErrJobNotFound)
+	_, _, err = s.JobRun("key", 400, 500, false, "", now, 8)
+	assert.ErrorIs(t, err, ErrJobNotFound)
+}
+
+func TestFSM_JobRunNotifiesWatchers(t *testing.T) {
+	fsm := newTestFSM(t)
+
+	res := applyCommand(t, fsm, 1, JobSetType, &types.Job{
+		Name: "j", Schedule: "@hourly", Key: "cron/out", Payload: "tick", NextRunAt: 100,
+	})
+	require.IsType(t, &Job{}, res)
+
+	w := fsm.watches.Watch("cron/", true)
+	defer fsm.watches.Stop(w)
+
+	res = applyCommand(t, fsm, 2, JobRunType, &types.JobRunRequest{
+		Name: "j", ScheduledAt: 100, NextRunAt: 200, Now: time.Now().UnixNano(),
+	})
+	require.IsType(t, &Job{}, res)
+	assert.Equal(t, uint64(2), <-w.ch)
+}
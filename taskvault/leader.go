@@ -1,12 +1,15 @@
 package taskvault
 
 import (
+	"errors"
 	"fmt"
 	"net"
 	"sync"
 	"time"
 
+	"github.com/danluki/taskvault/pkg/types"
 	metrics "github.com/hashicorp/go-metrics"
+	"github.com/hashicorp/go-uuid"
 	"github.com/hashicorp/raft"
 	"github.com/hashicorp/serf/serf"
 	"go.uber.org/zap"
@@ -16,6 +19,14 @@ const (
 	barrierWriteTimeout = 2 * time.Minute
 )
 
+var (
+	ErrUnknownPeer = errors.New("no raft peer with that id")
+	ErrLastVoter   = errors.New("refusing to remove the last voter")
+	// ErrNoTransferTarget is returned when leadership can not be transferred
+	// because there is no other voter, or the requested server is not one.
+	ErrNoTransferTarget = errors.New("no other voter to transfer leadership to")
+)
+
 func (a *Agent) monitorLeadership() {
 	var weAreLeaderCh chan struct{}
 	var leaderLoop sync.WaitGroup
@@ -59,6 +70,17 @@ func (a *Agent) monitorLeadership() {
 
 func (a *Agent) leaderLoop(stopCh chan struct{}) {
 	var refreshCh chan serf.Member
+	defer a.readyForReads.Store(false)
+	defer a.autopilot.reset()
+
+	expiry := time.NewTicker(a.config.ExpiryInterval)
+	defer expiry.Stop()
+
+	autopilot := time.NewTicker(a.config.AutopilotInterval)
+	defer autopilot.Stop()
+
+	jobs := time.NewTicker(a.config.JobInterval)
+	defer jobs.Stop()
 
 REFRESH:
 	refreshCh = nil
@@ -71,6 +93,7 @@ REFRESH:
 		goto WAIT
 	}
 	metrics.MeasureSince([]string{"taskvault", "leader", "barrier"}, start)
+	a.readyForReads.Store(true)
 
 	if err := a.Refresh(); err != nil {
 		a.logger.Error("failed to ", zap.Error(err))
@@ -98,8 +121,213 @@ WAIT:
 			if err := a.RefreshMember(member); err != nil {
 				a.logger.Error("taskvault: failed to Refresh member", zap.Error(err))
 			}
+		case <-expiry.C:
+			if err := a.reapExpiredKeys(); err != nil {
+				a.logger.Error("taskvault: failed to reap expired keys", zap.Error(err))
+			}
+			if err := a.reapExpiredSessions(); err != nil {
+				a.logger.Error("taskvault: failed to reap expired sessions", zap.Error(err))
+			}
+			if err := a.reapExpiredLeases(); err != nil {
+				a.logger.Error("taskvault: failed to reap expired leases", zap.Error(err))
+			}
+		case <-jobs.C:
+			if err := a.runDueJobs(); err != nil {
+				a.logger.Error("taskvault: failed to run jobs", zap.Error(err))
+			}
+		case <-autopilot.C:
+			if err := a.autopilot.run(); err != nil {
+				a.logger.Error("taskvault: autopilot failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// reapExpiredKeys deletes keys whose TTL ran out. Expiry is decided here and
+// replicated as regular deletes so that all replicas drop the key at the same
+// log index. The delete is guarded by the modify index that was seen expired,
+// so a key refreshed in the meantime is left alone.
+func (a *Agent) reapExpiredKeys() error {
+	defer metrics.MeasureSince(
+		[]string{"taskvault", "leader", "reapExpiredKeys"}, time.Now(),
+	)
+
+	pairs, err := a.Store.ExpiredValues(time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, pair := range pairs {
+		modifyIndex := pair.ModifyIndex
+		_, err := a.raftApply(DeletePairType, &types.DeleteValueRequest{
+			Key: pair.Key,
+			Cas: &modifyIndex,
+		})
+		if err != nil && !errors.Is(err, ErrCASConflict) && !errors.Is(err, ErrKeyNotFound) {
+			return err
+		}
+		a.logger.Debug("taskvault: expired key", zap.String("key", pair.Key))
+	}
+
+	return nil
+}
+
+// reapExpiredSessions destroys the sessions that were not renewed in time,
+// freeing their locks. Like expired keys, the destroy is guarded by the
+// modify index that was seen expired so a renewal in the meantime wins.
+func (a *Agent) reapExpiredSessions() error {
+	sessions, err := a.Store.ExpiredSessions(time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		modifyIndex := session.ModifyIndex
+		_, err := a.raftApply(SessionDestroyType, &types.SessionRequest{
+			Id:  session.ID,
+			Cas: &modifyIndex,
+		})
+		if err != nil && !errors.Is(err, ErrCASConflict) && !errors.Is(err, ErrSessionNotFound) {
+			return err
+		}
+		a.logger.With(zap.String("session", session.ID)).Debug("taskvault: expired session")
+	}
+
+	return nil
+}
+
+// reapExpiredLeases hands the tasks whose worker did not ack or nack them
+// within the visibility timeout back to their queue, or dead-letters them
+// when they are out of attempts. The nack carries the claim that was seen
+// expired, so a worker that acked in the meantime wins.
+func (a *Agent) reapExpiredLeases() error {
+	now := time.Now()
+	tasks, err := a.Store.ExpiredLeases(now)
+	if err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		_, err := a.raftApply(QueueNackType, &types.QueueTaskRequest{
+			Queue:     task.Queue,
+			Id:        task.ID,
+			ClaimId:   task.ClaimID,
+			Error:     leaseExpiredError,
+			VisibleAt: now.UnixNano(),
+		})
+		if err != nil && !errors.Is(err, ErrTaskNotClaimed) && !errors.Is(err, ErrTaskNotFound) {
+			return err
+		}
+		a.logger.With(
+			zap.String("queue", task.Queue),
+			zap.String("task", task.ID),
+		).Debug("taskvault: expired task lease")
+	}
+
+	return nil
+}
+
+// runDueJobs fires the jobs whose next run has come. Each run is applied
+// with the scheduled time it fires, the store only records it while that is
+// still the next run of the job, so a run is never fired twice, not even by
+// a new leader retrying it. A run later than jobMisfireThreshold was missed,
+// the catch-up policy of the job decides whether it fires.
+func (a *Agent) runDueJobs() error {
+	defer metrics.MeasureSince(
+		[]string{"taskvault", "leader", "runDueJobs"}, time.Now(),
+	)
+
+	jobs, err := a.Store.JobList()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, job := range jobs {
+		if job.NextRunAt == 0 || job.NextRunAt > now.UnixNano() {
+			continue
+		}
+		schedule, err := parseCron(job.Schedule)
+		if err != nil {
+			a.logger.With(zap.String("job", job.Name), zap.Error(err)).Error("taskvault: invalid job schedule")
+			continue
+		}
+
+		if err := a.runJob(&job, schedule, now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a *Agent) runJob(job *Job, schedule *cronSchedule, now time.Time) error {
+	scheduledAt := job.NextRunAt
+	for runs := 0; scheduledAt != 0 && scheduledAt <= now.UnixNano() && runs < maxJobRunsPerTick; runs++ {
+		req := &types.JobRunRequest{
+			Name:        job.Name,
+			ScheduledAt: scheduledAt,
+			NextRunAt:   nextRun(schedule, time.Unix(0, scheduledAt)),
+			Now:         now.UnixNano(),
+		}
+		if now.Sub(time.Unix(0, scheduledAt)) > jobMisfireThreshold {
+			switch job.CatchUp {
+			case JobCatchUpSkip:
+				req.Skip = true
+				req.NextRunAt = nextRun(schedule, now)
+			case JobCatchUpOnce:
+				req.NextRunAt = nextRun(schedule, now)
+			}
+		}
+		if job.Queue != "" && !req.Skip {
+			id, err := uuid.GenerateUUID()
+			if err != nil {
+				return err
+			}
+			req.TaskId = id
+		}
+
+		_, err := a.raftApply(JobRunType, req)
+		if errors.Is(err, errJobRunRecorded) || errors.Is(err, ErrJobNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		a.logger.With(
+			zap.String("job", job.Name),
+			zap.Bool("skipped", req.Skip),
+		).Debug("taskvault: ran job")
+
+		scheduledAt = req.NextRunAt
+	}
+
+	return nil
+}
+
+// destroyNodeSessions destroys the sessions tied to a node that failed or
+// left the cluster, freeing their locks.
+func (a *Agent) destroyNodeSessions(node string) error {
+	sessions, err := a.Store.SessionList()
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		if session.Node != node {
+			continue
+		}
+		_, err := a.raftApply(SessionDestroyType, &types.SessionRequest{Id: session.ID})
+		if err != nil && !errors.Is(err, ErrSessionNotFound) {
+			return err
 		}
+		a.logger.With(
+			zap.String("session", session.ID),
+			zap.String("node", node),
+		).Info("taskvault: destroyed session of node")
 	}
+
+	return nil
 }
 
 func (a *Agent) Refresh() error {
@@ -117,6 +345,14 @@ func (a *Agent) Refresh() error {
 }
 
 func (a *Agent) RefreshMember(member serf.Member) error {
+	switch member.Status {
+	case serf.StatusFailed, serf.StatusLeft, StatusReap:
+		if err := a.destroyNodeSessions(member.Name); err != nil {
+			a.logger.Error("failed to destroy sessions", zap.Error(err), zap.Any("member", member))
+			return err
+		}
+	}
+
 	parts := toServerPart(member)
 	if parts == nil {
 		return nil
@@ -178,10 +414,21 @@ func (a *Agent) addRaftPeer(m serf.Member, parts *ServerParts) error {
 		}
 	}
 
-	for _, server := range configFuture.Configuration().Servers {
+	servers := configFuture.Configuration().Servers
+	for _, server := range servers {
 		if server.Address == raft.ServerAddress(addr) || server.ID == raft.ServerID(parts.ID) {
 			if server.Address == raft.ServerAddress(addr) && server.ID == raft.ServerID(parts.ID) {
-				return nil
+				if (server.Suffrage == raft.Voter) != parts.NonVoter {
+					return nil
+				}
+				if !parts.NonVoter {
+					// AddVoter below promotes the replica.
+					break
+				}
+				if err := checkPeerRemoval(servers, server.ID); err != nil {
+					return err
+				}
+				return a.raft.DemoteVoter(server.ID, 0, 0).Error()
 			}
 			if server.Address == raft.ServerAddress(addr) {
 				future := a.raft.RemoveServer(server.ID, 0, 0)
@@ -196,9 +443,16 @@ func (a *Agent) addRaftPeer(m serf.Member, parts *ServerParts) error {
 		}
 	}
 
-	addFuture := a.raft.AddVoter(
-		raft.ServerID(parts.ID), raft.ServerAddress(addr), 0, 0,
-	)
+	var addFuture raft.IndexFuture
+	if parts.NonVoter {
+		addFuture = a.raft.AddNonvoter(
+			raft.ServerID(parts.ID), raft.ServerAddress(addr), 0, 0,
+		)
+	} else {
+		addFuture = a.raft.AddVoter(
+			raft.ServerID(parts.ID), raft.ServerAddress(addr), 0, 0,
+		)
+	}
 	if err := addFuture.Error(); err != nil {
 		return err
 	}
@@ -232,3 +486,126 @@ func (a *Agent) removeRaftPeer(m serf.Member, parts *ServerParts) error {
 
 	return nil
 }
+
+// removeRaftPeerByID removes the server with the given id from the Raft
+// configuration. It must run on the leader. Removing the last voter would
+// leave the cluster unable to elect a leader, so it is refused.
+func (a *Agent) removeRaftPeerByID(id raft.ServerID) error {
+	configFuture := a.raft.GetConfiguration()
+	if err := configFuture.Error(); err != nil {
+		return err
+	}
+
+	if err := checkPeerRemoval(configFuture.Configuration().Servers, id); err != nil {
+		return err
+	}
+
+	// Pass the index of the checked configuration so the removal fails if
+	// the configuration changed in the meantime.
+	future := a.raft.RemoveServer(id, configFuture.Index(), 0)
+	if err := future.Error(); err != nil {
+		return err
+	}
+
+	a.logger.With(zap.String("id", string(id))).Info("taskvault: removed raft peer")
+
+	return nil
+}
+
+// checkPeerRemoval verifies that the server with the given id can be removed
+// from servers.
+func checkPeerRemoval(servers []raft.Server, id raft.ServerID) error {
+	var found, voter bool
+	voters := 0
+	for _, server := range servers {
+		if server.Suffrage == raft.Voter {
+			voters++
+		}
+		if server.ID == id {
+			found = true
+			voter = server.Suffrage == raft.Voter
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("%w: %s", ErrUnknownPeer, id)
+	}
+	if voter && voters == 1 {
+		return ErrLastVoter
+	}
+
+	return nil
+}
+
+// transferLeadership hands leadership over to the voter with the given id,
+// or to the most up to date voter when id is empty. It must run on the
+// leader and returns the new leader once it is known.
+func (a *Agent) transferLeadership(id raft.ServerID) (raft.ServerAddress, raft.ServerID, error) {
+	configFuture := a.raft.GetConfiguration()
+	if err := configFuture.Error(); err != nil {
+		return "", "", err
+	}
+
+	target, err := transferTarget(
+		configFuture.Configuration().Servers, raft.ServerID(a.config.NodeName), id,
+	)
+	if err != nil {
+		return "", "", err
+	}
+
+	var future raft.Future
+	if target == nil {
+		future = a.raft.LeadershipTransfer()
+	} else {
+		future = a.raft.LeadershipTransferToServer(target.ID, target.Address)
+	}
+	if err := future.Error(); err != nil {
+		return "", "", err
+	}
+
+	// The new leader is only known here once it sent its first heartbeat.
+	deadline := time.Now().Add(writeHoldTimeout)
+	for {
+		addr, leaderID := a.raft.LeaderWithID()
+		if addr != "" {
+			a.logger.With(zap.String("leader", string(leaderID))).
+				Info("taskvault: transferred leadership")
+			return addr, leaderID, nil
+		}
+		if time.Now().After(deadline) {
+			return "", "", fmt.Errorf("%w: none elected after the transfer", ErrNoLeader)
+		}
+		time.Sleep(writeRetryInterval)
+	}
+}
+
+// transferTarget returns the server leadership should be transferred to, or
+// nil when raft may pick any voter other than self.
+func transferTarget(servers []raft.Server, self, id raft.ServerID) (*raft.Server, error) {
+	var target *raft.Server
+	otherVoters := 0
+	for i, server := range servers {
+		if server.Suffrage == raft.Voter && server.ID != self {
+			otherVoters++
+		}
+		if server.ID == id {
+			target = &servers[i]
+		}
+	}
+
+	if id == "" {
+		if otherVoters == 0 {
+			return nil, ErrNoTransferTarget
+		}
+		return nil, nil
+	}
+
+	if target == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownPeer, id)
+	}
+	if target.ID == self || target.Suffrage != raft.Voter {
+		return nil, fmt.Errorf("%w: %s", ErrNoTransferTarget, id)
+	}
+
+	return target, nil
+}
@@ -0,0 +1,46 @@
+package taskvault
+
+import (
+	"testing"
+
+	"github.com/hashicorp/raft"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckPeerRemoval(t *testing.T) {
+	servers := []raft.Server{
+		{ID: "a", Suffrage: raft.Voter},
+		{ID: "b", Suffrage: raft.Nonvoter},
+	}
+
+	assert.ErrorIs(t, checkPeerRemoval(servers, "c"), ErrUnknownPeer)
+	assert.ErrorIs(t, checkPeerRemoval(servers, "a"), ErrLastVoter)
+	assert.NoError(t, checkPeerRemoval(servers, "b"))
+
+	servers = append(servers, raft.Server{ID: "c", Suffrage: raft.Voter})
+	assert.NoError(t, checkPeerRemoval(servers, "a"))
+}
+
+func TestTransferTarget(t *testing.T) {
+	servers := []raft.Server{
+		{ID: "a", Suffrage: raft.Voter},
+		{ID: "b", Suffrage: raft.Nonvoter},
+	}
+
+	_, err := transferTarget(servers, "a", "")
+	assert.ErrorIs(t, err, ErrNoTransferTarget)
+	_, err = transferTarget(servers, "a", "b")
+	assert.ErrorIs(t, err, ErrNoTransferTarget)
+	_, err = transferTarget(servers, "a", "a")
+	assert.ErrorIs(t, err, ErrNoTransferTarget)
+	_, err = transferTarget(servers, "a", "c")
+	assert.ErrorIs(t, err, ErrUnknownPeer)
+
+	servers = append(servers, raft.Server{ID: "c", Suffrage: raft.Voter})
+	target, err := transferTarget(servers, "a", "")
+	assert.NoError(t, err)
+	assert.Nil(t, target)
+	target, err = transferTarget(servers, "a", "c")
+	assert.NoError(t, err)
+	assert.Equal(t, raft.ServerID("c"), target.ID)
+}
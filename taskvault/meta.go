@@ -0,0 +1,70 @@
+package taskvault
+
+import (
+	"fmt"
+
+	"github.com/danluki/taskvault/pkg/types"
+)
+
+// Bounds of the metadata of a key, metadata is meant to tag a value, not to
+// hold data of its own.
+const (
+	maxContentTypeSize = 256
+	maxLabels          = 64
+	maxLabelSize       = 256
+)
+
+// KeyMeta is metadata stored alongside the value of a key. A write carrying
+// metadata replaces all of it, a write without keeps the current metadata.
+type KeyMeta struct {
+	// Flags are opaque to the server, clients use them as they see fit.
+	Flags       uint64            `json:",omitempty"`
+	ContentType string            `json:",omitempty"`
+	Labels      map[string]string `json:",omitempty"`
+}
+
+func keyMetaFromProto(m *types.KeyMeta) *KeyMeta {
+	if m == nil {
+		return nil
+	}
+
+	return &KeyMeta{
+		Flags:       m.Flags,
+		ContentType: m.ContentType,
+		Labels:      m.Labels,
+	}
+}
+
+func (m *KeyMeta) proto() *types.KeyMeta {
+	if m == nil {
+		return nil
+	}
+
+	return &types.KeyMeta{
+		Flags:       m.Flags,
+		ContentType: m.ContentType,
+		Labels:      m.Labels,
+	}
+}
+
+func validateKeyMeta(m *types.KeyMeta) error {
+	if m == nil {
+		return nil
+	}
+	if len(m.ContentType) > maxContentTypeSize {
+		return fmt.Errorf("content type longer than %d bytes", maxContentTypeSize)
+	}
+	if len(m.Labels) > maxLabels {
+		return fmt.Errorf("%d labels, at most %d are allowed", len(m.Labels), maxLabels)
+	}
+	for k, v := range m.Labels {
+		if k == "" {
+			return fmt.Errorf("label name is required")
+		}
+		if len(k) > maxLabelSize || len(v) > maxLabelSize {
+			return fmt.Errorf("label %q longer than %d bytes", k, maxLabelSize)
+		}
+	}
+
+	return nil
+}
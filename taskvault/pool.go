@@ -0,0 +1,115 @@
+package taskvault
+
+import (
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+)
+
+const (
+	// poolMaxIdle is how long a connection may stay unused before it is
+	// closed, so connections to departed servers do not pile up.
+	poolMaxIdle    = 2 * time.Minute
+	poolReapPeriod = 30 * time.Second
+	// poolMaxBackoff caps the reconnect delay, the default of two minutes
+	// would keep a restarted server unreachable for too long.
+	poolMaxBackoff = 5 * time.Second
+)
+
+type pooledConn struct {
+	conn     *grpc.ClientConn
+	lastUsed time.Time
+}
+
+// connPool keeps one gRPC connection per server address. Connections are
+// shared by concurrent calls and reconnect on their own, callers must not
+// close them.
+type connPool struct {
+	dialOpt []grpc.DialOption
+
+	lock     sync.Mutex
+	conns    map[string]*pooledConn
+	shutdown bool
+	stopCh   chan struct{}
+}
+
+func newConnPool(dialOpt ...grpc.DialOption) *connPool {
+	backoffConfig := backoff.DefaultConfig
+	backoffConfig.MaxDelay = poolMaxBackoff
+
+	p := &connPool{
+		dialOpt: append(dialOpt, grpc.WithConnectParams(grpc.ConnectParams{
+			Backoff:           backoffConfig,
+			MinConnectTimeout: leaderConnectTimeout,
+		})),
+		conns:  make(map[string]*pooledConn),
+		stopCh: make(chan struct{}),
+	}
+	go p.reap()
+
+	return p
+}
+
+// Get returns the connection to addr, dialing it on first use.
+func (p *connPool) Get(addr string) (*grpc.ClientConn, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.shutdown {
+		return nil, grpc.ErrClientConnClosing
+	}
+
+	if pc, ok := p.conns[addr]; ok {
+		pc.lastUsed = time.Now()
+		return pc.conn, nil
+	}
+
+	conn, err := grpc.NewClient(addr, p.dialOpt...)
+	if err != nil {
+		return nil, err
+	}
+	p.conns[addr] = &pooledConn{conn: conn, lastUsed: time.Now()}
+
+	return conn, nil
+}
+
+// Shutdown closes every connection. The pool can not be used afterwards.
+func (p *connPool) Shutdown() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.shutdown {
+		return
+	}
+	p.shutdown = true
+	close(p.stopCh)
+
+	for addr, pc := range p.conns {
+		_ = pc.conn.Close()
+		delete(p.conns, addr)
+	}
+}
+
+func (p *connPool) reap() {
+	ticker := time.NewTicker(poolReapPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-p.stopCh:
+			return
+		}
+
+		p.lock.Lock()
+		for addr, pc := range p.conns {
+			if time.Since(pc.lastUsed) > poolMaxIdle {
+				_ = pc.conn.Close()
+				delete(p.conns, addr)
+			}
+		}
+		p.lock.Unlock()
+	}
+}
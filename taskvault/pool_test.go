@@ -0,0 +1,28 @@
+package taskvault
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestConnPool(t *testing.T) {
+	p := newConnPool(grpc.WithTransportCredentials(insecure.NewCredentials()))
+
+	c1, err := p.Get("127.0.0.1:6868")
+	require.NoError(t, err)
+	c2, err := p.Get("127.0.0.1:6868")
+	require.NoError(t, err)
+	assert.Same(t, c1, c2, "connections are reused")
+
+	c3, err := p.Get("127.0.0.1:6869")
+	require.NoError(t, err)
+	assert.NotSame(t, c1, c3)
+
+	p.Shutdown()
+	_, err = p.Get("127.0.0.1:6868")
+	assert.Error(t, err)
+}
@@ -0,0 +1,176 @@
+package taskvault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/danluki/taskvault/pkg/types"
+	"github.com/hashicorp/raft"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// forwardedKey marks reads that were forwarded to the leader, so a node
+// that lost leadership in the meantime fails them instead of forwarding
+// them again.
+const forwardedKey = "x-taskvault-forwarded"
+
+var (
+	ErrNoLeader                   = errors.New("no cluster leader")
+	ErrNotReadyForConsistentReads = errors.New("not ready to serve consistent reads")
+)
+
+var consistencyModes = map[string]types.Consistency{
+	"default":      types.Consistency_CONSISTENCY_DEFAULT,
+	"stale":        types.Consistency_CONSISTENCY_STALE,
+	"linearizable": types.Consistency_CONSISTENCY_LINEARIZABLE,
+}
+
+// QueryMeta describes how fresh the result of a read is.
+type QueryMeta struct {
+	// KnownLeader is set when the node serving the read knew of a leader.
+	KnownLeader bool
+	// LastContact is the time since the serving node last heard from the
+	// leader, 0 when it is the leader.
+	LastContact time.Duration
+}
+
+func parseConsistency(mode string) (types.Consistency, error) {
+	if mode == "" {
+		return types.Consistency_CONSISTENCY_DEFAULT, nil
+	}
+
+	c, ok := consistencyModes[strings.ToLower(mode)]
+	if !ok {
+		return 0, fmt.Errorf("unknown consistency mode %q", mode)
+	}
+
+	return c, nil
+}
+
+// getValue reads key with the given consistency. Stale reads are served
+// from the local store, other modes are served by the leader and are
+// forwarded to it when this node is a follower.
+func (a *Agent) getValue(
+	ctx context.Context, key string, mode types.Consistency,
+) (*Pair, QueryMeta, error) {
+	if !aclFromContext(ctx).KeyRead(key) {
+		return nil, QueryMeta{}, ErrPermissionDenied
+	}
+
+	leader, err := a.readTarget(ctx, mode)
+	if err != nil {
+		return nil, QueryMeta{}, err
+	}
+	if leader != "" {
+		return a.requestClient(ctx).GetValue(leader, &types.GetValueRequest{
+			Key:         key,
+			Consistency: mode,
+		})
+	}
+
+	pair, err := a.Store.GetValue(key)
+	return pair, a.queryMeta(), err
+}
+
+// listValues is the listing counterpart of getValue. Keys the caller may
+// not read are left out.
+func (a *Agent) listValues(
+	ctx context.Context, opts ListOptions, mode types.Consistency,
+) ([]Pair, int, QueryMeta, error) {
+	leader, err := a.readTarget(ctx, mode)
+	if err != nil {
+		return nil, 0, QueryMeta{}, err
+	}
+	if leader != "" {
+		return a.requestClient(ctx).ListValues(leader, &types.GetAllPairsRequest{
+			Prefix:      opts.Prefix,
+			StartKey:    opts.StartKey,
+			EndKey:      opts.EndKey,
+			KeysOnly:    opts.KeysOnly,
+			Separator:   opts.Separator,
+			Consistency: mode,
+			Offset:      int64(opts.Offset),
+			Limit:       int64(opts.Limit),
+		})
+	}
+
+	if authz := aclFromContext(ctx); authz != nil {
+		opts.Filter = authz.KeyRead
+	}
+	pairs, total, err := a.Store.ListValues(opts)
+	return pairs, total, a.queryMeta(), err
+}
+
+// readTarget returns the address of the leader when a read with the given
+// consistency has to be forwarded to it, or an empty address when this node
+// can serve it. Linearizable reads on the leader first confirm that it
+// still holds a quorum.
+func (a *Agent) readTarget(ctx context.Context, mode types.Consistency) (string, error) {
+	if mode == types.Consistency_CONSISTENCY_STALE {
+		return "", nil
+	}
+
+	if a.IsLeader() {
+		if mode == types.Consistency_CONSISTENCY_LINEARIZABLE {
+			return "", a.verifyLeader()
+		}
+		return "", nil
+	}
+
+	if isForwarded(ctx) {
+		return "", raft.ErrNotLeader
+	}
+
+	leader := a.raft.Leader()
+	if leader == "" {
+		return "", ErrNoLeader
+	}
+
+	return string(leader), nil
+}
+
+// verifyLeader confirms with a quorum that this node is still the leader
+// and that the entries committed by previous leaders have been applied.
+func (a *Agent) verifyLeader() error {
+	if err := a.raft.VerifyLeader().Error(); err != nil {
+		return err
+	}
+	if !a.readyForReads.Load() {
+		return ErrNotReadyForConsistentReads
+	}
+
+	return nil
+}
+
+func (a *Agent) queryMeta() QueryMeta {
+	if a.IsLeader() {
+		return QueryMeta{KnownLeader: true}
+	}
+
+	meta := QueryMeta{KnownLeader: a.raft.Leader() != ""}
+	if last := a.raft.LastContact(); !last.IsZero() {
+		meta.LastContact = time.Since(last)
+	}
+
+	return meta
+}
+
+func isForwarded(ctx context.Context) bool {
+	md, ok := metadata.FromIncomingContext(ctx)
+	return ok && len(md.Get(forwardedKey)) > 0
+}
+
+// isUnavailable reports whether a read failed because no leader could
+// serve it, which clients may retry.
+func isUnavailable(err error) bool {
+	return errors.Is(err, ErrNoLeader) ||
+		errors.Is(err, ErrNotReadyForConsistentReads) ||
+		errors.Is(err, raft.ErrNotLeader) ||
+		errors.Is(err, raft.ErrLeadershipLost) ||
+		status.Code(err) == codes.Unavailable
+}
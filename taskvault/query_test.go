@@ -0,0 +1,33 @@
+package taskvault
+
+import (
+	"context"
+	"testing"
+
+	"github.com/danluki/taskvault/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestParseConsistency(t *testing.T) {
+	mode, err := parseConsistency("")
+	require.NoError(t, err)
+	assert.Equal(t, types.Consistency_CONSISTENCY_DEFAULT, mode)
+
+	mode, err = parseConsistency("Linearizable")
+	require.NoError(t, err)
+	assert.Equal(t, types.Consistency_CONSISTENCY_LINEARIZABLE, mode)
+
+	_, err = parseConsistency("strong")
+	assert.Error(t, err)
+}
+
+func TestIsForwarded(t *testing.T) {
+	assert.False(t, isForwarded(context.Background()))
+
+	ctx := metadata.NewIncomingContext(
+		context.Background(), metadata.Pairs(forwardedKey, "true"),
+	)
+	assert.True(t, isForwarded(ctx))
+}
@@ -0,0 +1,116 @@
+package taskvault
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/danluki/taskvault/pkg/types"
+)
+
+// States of a task in a queue.
+const (
+	TaskPending = "pending"
+	TaskClaimed = "claimed"
+	TaskDead    = "dead"
+)
+
+const (
+	// defaultMaxAttempts is used for tasks enqueued without max attempts.
+	defaultMaxAttempts = 3
+	// defaultVisibilityTimeout is used for claims without a timeout.
+	defaultVisibilityTimeout = 30 * time.Second
+	// leaseExpiredError is the reason recorded when a lease runs out.
+	leaseExpiredError = "lease expired"
+)
+
+var (
+	ErrTaskNotFound   = errors.New("task not found")
+	ErrTaskNotClaimed = errors.New("task not claimed")
+)
+
+// Task is a unit of work in a queue. A worker claims it for a visibility
+// timeout and acks it when done. Tasks that are nacked or whose lease runs
+// out are retried until MaxAttempts claims failed, then dead-lettered.
+type Task struct {
+	ID       string
+	Queue    string
+	Payload  string
+	Priority int64
+	// VisibleAt is when a pending task can be claimed, or when the lease of
+	// a claimed task runs out, in unix nanoseconds.
+	VisibleAt   int64
+	Attempts    uint32
+	MaxAttempts uint32
+	State       string
+	ClaimID     string `json:",omitempty"`
+	LastError   string `json:",omitempty"`
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// QueueInfo counts the tasks of a queue by state.
+type QueueInfo struct {
+	Name    string
+	Pending int
+	Claimed int
+	Dead    int
+}
+
+func taskFromProto(t *types.Task) *Task {
+	return &Task{
+		ID:          t.Id,
+		Queue:       t.Queue,
+		Payload:     t.Payload,
+		Priority:    t.Priority,
+		VisibleAt:   t.VisibleAt,
+		Attempts:    t.Attempts,
+		MaxAttempts: t.MaxAttempts,
+		State:       t.State,
+		ClaimID:     t.ClaimId,
+		LastError:   t.LastError,
+		CreateIndex: t.CreateIndex,
+		ModifyIndex: t.ModifyIndex,
+	}
+}
+
+func (t *Task) proto() *types.Task {
+	return &types.Task{
+		Id:          t.ID,
+		Queue:       t.Queue,
+		Payload:     t.Payload,
+		Priority:    t.Priority,
+		VisibleAt:   t.VisibleAt,
+		Attempts:    t.Attempts,
+		MaxAttempts: t.MaxAttempts,
+		State:       t.State,
+		ClaimId:     t.ClaimID,
+		LastError:   t.LastError,
+		CreateIndex: t.CreateIndex,
+		ModifyIndex: t.ModifyIndex,
+	}
+}
+
+// validateQueueName checks that name can be used as a single key segment.
+func validateQueueName(name string) error {
+	if name == "" {
+		return errors.New("queue name is required")
+	}
+	if strings.Contains(name, "/") {
+		return fmt.Errorf("queue name %q must not contain a slash", name)
+	}
+
+	return nil
+}
+
+// queueACLKey is the key whose rules govern access to a queue.
+func queueACLKey(name string) string {
+	return "queues/" + name
+}
+
+// afterSeconds returns the time d seconds from now in unix nanoseconds. Like
+// expiresAt it is computed by the leader before the command enters the log.
+func afterSeconds(d int64) int64 {
+	return time.Now().Add(time.Duration(d) * time.Second).UnixNano()
+}
@@ -0,0 +1,186 @@
+package taskvault
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/danluki/taskvault/pkg/types"
+	"github.com/gin-gonic/gin"
+)
+
+type queueEnqueueRequest struct {
+	Payload  string
+	Priority int64
+	// Delay is in seconds.
+	Delay       int64
+	MaxAttempts uint32
+}
+
+type queueTaskRequest struct {
+	ClaimID string
+	// Delay is in seconds, only used by nacks.
+	Delay int64
+	Error string
+}
+
+// queueListHandler returns the queues holding tasks with their counts,
+// limited to the queues the token can read.
+func (h *HTTPTransport) queueListHandler(c *gin.Context) {
+	queues, err := h.agent.Store.QueueList()
+	if err != nil {
+		h.renderReadError(c, err)
+		return
+	}
+
+	authz := aclFromContext(c.Request.Context())
+	visible := []QueueInfo{}
+	for _, q := range queues {
+		if authz.KeyRead(queueACLKey(q.Name)) {
+			visible = append(visible, q)
+		}
+	}
+	renderJSON(c, http.StatusOK, visible)
+}
+
+// queueTasksHandler lists the tasks of a queue, ?state= limits them to
+// pending, claimed or dead tasks.
+func (h *HTTPTransport) queueTasksHandler(c *gin.Context) {
+	name := c.Param("name")
+	if !aclFromContext(c.Request.Context()).KeyRead(queueACLKey(name)) {
+		_ = c.AbortWithError(http.StatusForbidden, ErrPermissionDenied)
+		return
+	}
+	state := c.Query("state")
+	switch state {
+	case "", TaskPending, TaskClaimed, TaskDead:
+	default:
+		_ = c.AbortWithError(http.StatusBadRequest, fmt.Errorf("unknown task state %q", state))
+		return
+	}
+
+	tasks, err := h.agent.Store.QueueTasks(name, state)
+	if err != nil {
+		h.renderReadError(c, err)
+		return
+	}
+
+	if tasks == nil {
+		tasks = []Task{}
+	}
+	renderJSON(c, http.StatusOK, tasks)
+}
+
+// queueEnqueueHandler adds a task from the Payload, Priority, Delay and
+// MaxAttempts fields of the body.
+func (h *HTTPTransport) queueEnqueueHandler(c *gin.Context) {
+	body := &queueEnqueueRequest{}
+	if err := c.ShouldBindJSON(body); err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	task, err := h.client(c).QueueEnqueue(&types.Task{
+		Queue:       c.Param("name"),
+		Payload:     body.Payload,
+		Priority:    body.Priority,
+		Delay:       body.Delay,
+		MaxAttempts: body.MaxAttempts,
+	})
+	if err != nil {
+		h.renderQueueError(c, err)
+		return
+	}
+
+	setIndexHeader(c, task.ModifyIndex)
+	renderJSON(c, http.StatusCreated, task)
+}
+
+// queueClaimHandler claims the next ready task for ?visibility= seconds. It
+// answers 204 when the queue has nothing ready.
+func (h *HTTPTransport) queueClaimHandler(c *gin.Context) {
+	var visibility int64
+	if v, ok := c.GetQuery("visibility"); ok {
+		var err error
+		visibility, err = strconv.ParseInt(v, 10, 64)
+		if err != nil || visibility <= 0 {
+			_ = c.AbortWithError(http.StatusBadRequest, fmt.Errorf("invalid visibility %q", v))
+			return
+		}
+	}
+
+	task, err := h.client(c).QueueClaim(c.Param("name"), visibility)
+	if err != nil {
+		h.renderQueueError(c, err)
+		return
+	}
+	if task == nil {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	setIndexHeader(c, task.ModifyIndex)
+	renderJSON(c, http.StatusOK, task)
+}
+
+// queueAckHandler completes a task claimed under the ClaimID of the body.
+func (h *HTTPTransport) queueAckHandler(c *gin.Context) {
+	body := &queueTaskRequest{}
+	if err := c.ShouldBindJSON(body); err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.client(c).QueueAck(c.Param("name"), c.Param("id"), body.ClaimID); err != nil {
+		h.renderQueueError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// queueNackHandler hands a task claimed under the ClaimID of the body back
+// to the queue after Delay seconds, recording Error as the reason.
+func (h *HTTPTransport) queueNackHandler(c *gin.Context) {
+	body := &queueTaskRequest{}
+	if err := c.ShouldBindJSON(body); err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	task, err := h.client(c).QueueNack(&types.QueueTaskRequest{
+		Queue:   c.Param("name"),
+		Id:      c.Param("id"),
+		ClaimId: body.ClaimID,
+		Delay:   body.Delay,
+		Error:   body.Error,
+	})
+	if err != nil {
+		h.renderQueueError(c, err)
+		return
+	}
+
+	setIndexHeader(c, task.ModifyIndex)
+	renderJSON(c, http.StatusOK, task)
+}
+
+func (h *HTTPTransport) queueDeleteHandler(c *gin.Context) {
+	if err := h.client(c).QueueDelete(c.Param("name"), c.Param("id")); err != nil {
+		h.renderQueueError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (h *HTTPTransport) renderQueueError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrTaskNotFound):
+		_ = c.AbortWithError(http.StatusNotFound, err)
+	case errors.Is(err, ErrTaskNotClaimed):
+		_ = c.AbortWithError(http.StatusConflict, err)
+	default:
+		h.renderWriteError(c, err)
+	}
+}
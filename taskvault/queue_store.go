@@ -0,0 +1,418 @@
+package taskvault
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tidwall/buntdb"
+)
+
+// Tasks live in the reserved key space under their queue, so they are
+// replicated and snapshotted like the sessions and ACLs.
+const queuePrefix = reservedPrefix + "queue/"
+
+// Tasks are indexed by records like the entries, see indexTask. Pending
+// tasks wait by visibility until a claim finds them visible and moves them
+// to the ready records, which are ordered for claiming. Claimed tasks are
+// recorded by the end of their lease.
+const (
+	queueWaitPrefix  = reservedPrefix + "qwait/"
+	queueReadyPrefix = reservedPrefix + "qready/"
+	queueLeasePrefix = reservedPrefix + "qlease/"
+)
+
+func taskKey(queue, id string) string {
+	return queuePrefix + queue + "/" + id
+}
+
+func taskWaitKey(t *Task) string {
+	return fmt.Sprintf("%s%s/%020d/%s", queueWaitPrefix, t.Queue, t.VisibleAt, t.ID)
+}
+
+func taskReadyKey(t *Task) string {
+	return fmt.Sprintf("%s%s/%020d/%020d/%s",
+		queueReadyPrefix, t.Queue, priorityRank(t.Priority), t.CreateIndex, t.ID)
+}
+
+func taskLeaseKey(t *Task) string {
+	return fmt.Sprintf("%s%020d/%s/%s", queueLeasePrefix, t.VisibleAt, t.Queue, t.ID)
+}
+
+// priorityRank maps priorities to ranks that sort the highest priority
+// first.
+func priorityRank(priority int64) uint64 {
+	return math.MaxUint64 - (uint64(priority) ^ 1<<63)
+}
+
+// indexTask moves the records of a task from its current state to t.
+// current is nil for a new task, t is nil for a deleted one.
+func indexTask(tx *buntdb.Tx, current, t *Task) error {
+	if current != nil {
+		var stale []string
+		switch current.State {
+		case TaskPending:
+			stale = []string{taskWaitKey(current), taskReadyKey(current)}
+		case TaskClaimed:
+			stale = []string{taskLeaseKey(current)}
+		}
+		for _, k := range stale {
+			if err := deleteRecord(tx, k); err != nil {
+				return err
+			}
+		}
+	}
+
+	if t != nil {
+		var record string
+		switch t.State {
+		case TaskPending:
+			record = taskWaitKey(t)
+		case TaskClaimed:
+			record = taskLeaseKey(t)
+		default:
+			return nil
+		}
+		if _, _, err := tx.Set(record, "", nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// promoteTasks moves the pending tasks of queue that are visible at now to
+// the ready records.
+func promoteTasks(tx *buntdb.Tx, queue string, now int64) error {
+	prefix := queueWaitPrefix + queue + "/"
+	var records []string
+	err := tx.AscendRange("", prefix, fmt.Sprintf("%s%020d", prefix, now+1), func(k, v string) bool {
+		records = append(records, k)
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, k := range records {
+		task, err := getTask(tx, queue, k[len(prefix)+21:])
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Delete(k); err != nil {
+			return err
+		}
+		_, _, err = tx.Set(taskReadyKey(task), strconv.FormatInt(task.VisibleAt, 10), nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// nextReadyTask returns the first ready task of queue visible at now, nil
+// when there is none. Ready tasks not visible yet were promoted by a leader
+// whose clock was ahead, they are passed over.
+func nextReadyTask(tx *buntdb.Tx, queue string, now int64) (*Task, error) {
+	prefix := queueReadyPrefix + queue + "/"
+	var id string
+	err := tx.AscendRange("", prefix, prefixEnd(prefix), func(k, v string) bool {
+		if visibleAt, _ := strconv.ParseInt(v, 10, 64); visibleAt > now {
+			return true
+		}
+		id = k[len(prefix)+42:]
+		return false
+	})
+	if err != nil || id == "" {
+		return nil, err
+	}
+
+	return getTask(tx, queue, id)
+}
+
+func getTask(tx *buntdb.Tx, queue, id string) (*Task, error) {
+	var task Task
+	if err := getJSON(tx, taskKey(queue, id), &task); err != nil {
+		if errors.Is(err, errRecordNotFound) {
+			return nil, ErrTaskNotFound
+		}
+		return nil, err
+	}
+
+	return &task, nil
+}
+
+func listTasks(tx *buntdb.Tx, prefix string, fn func(t *Task)) error {
+	return listJSON(tx, prefix, func(raw string) error {
+		var task Task
+		if err := json.Unmarshal([]byte(raw), &task); err != nil {
+			return err
+		}
+		fn(&task)
+		return nil
+	})
+}
+
+// claimedTask returns the task when it is claimed under claimID.
+func claimedTask(tx *buntdb.Tx, queue, id, claimID string) (*Task, error) {
+	task, err := getTask(tx, queue, id)
+	if err != nil {
+		return nil, err
+	}
+	if task.State != TaskClaimed || task.ClaimID != claimID {
+		return nil, fmt.Errorf("%w: task %q is %s", ErrTaskNotClaimed, id, task.State)
+	}
+
+	return task, nil
+}
+
+// QueueEnqueue stores a new pending task.
+func (s *Store) QueueEnqueue(task *Task, index uint64) (*Task, error) {
+	var stored *Task
+
+	err := s.db.Update(func(tx *buntdb.Tx) error {
+		var err error
+		if stored, err = enqueueTask(tx, task, index); err != nil {
+			return err
+		}
+		return setAppliedIndex(tx, index)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return stored, nil
+}
+
+func enqueueTask(tx *buntdb.Tx, task *Task, index uint64) (*Task, error) {
+	if err := validateQueueName(task.Queue); err != nil {
+		return nil, err
+	}
+	if task.ID == "" {
+		return nil, errors.New("task id is required")
+	}
+
+	stored := *task
+	stored.State = TaskPending
+	stored.Attempts = 0
+	stored.ClaimID = ""
+	stored.LastError = ""
+	stored.CreateIndex, stored.ModifyIndex = index, index
+
+	if _, err := tx.Get(taskKey(stored.Queue, stored.ID)); err == nil {
+		return nil, fmt.Errorf("task %q already exists", stored.ID)
+	} else if !errors.Is(err, buntdb.ErrNotFound) {
+		return nil, err
+	}
+
+	if err := setJSON(tx, taskKey(stored.Queue, stored.ID), &stored); err != nil {
+		return nil, err
+	}
+	if err := indexTask(tx, nil, &stored); err != nil {
+		return nil, err
+	}
+
+	return &stored, nil
+}
+
+// QueueClaim leases the next ready task of the queue to claimID until
+// visibleUntil. Tasks with a higher priority go first, then the oldest. It
+// returns nil when no task is ready at now.
+func (s *Store) QueueClaim(
+	queue string, now, visibleUntil int64, claimID string, index uint64,
+) (*Task, error) {
+	var claimed *Task
+
+	err := s.db.Update(func(tx *buntdb.Tx) error {
+		if err := promoteTasks(tx, queue, now); err != nil {
+			return err
+		}
+		var err error
+		claimed, err = nextReadyTask(tx, queue, now)
+		if err != nil {
+			return err
+		}
+
+		if claimed != nil {
+			current := *claimed
+			claimed.State = TaskClaimed
+			claimed.ClaimID = claimID
+			claimed.VisibleAt = visibleUntil
+			claimed.Attempts++
+			claimed.ModifyIndex = index
+			if err := setJSON(tx, taskKey(queue, claimed.ID), claimed); err != nil {
+				return err
+			}
+			if err := indexTask(tx, &current, claimed); err != nil {
+				return err
+			}
+		}
+		return setAppliedIndex(tx, index)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return claimed, nil
+}
+
+// QueueAck removes a task that was processed under claimID.
+func (s *Store) QueueAck(queue, id, claimID string, index uint64) error {
+	return s.db.Update(func(tx *buntdb.Tx) error {
+		task, err := claimedTask(tx, queue, id, claimID)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Delete(taskKey(queue, id)); err != nil {
+			return err
+		}
+		if err := indexTask(tx, task, nil); err != nil {
+			return err
+		}
+		return setAppliedIndex(tx, index)
+	})
+}
+
+// QueueNack gives a task claimed under claimID back to the queue, to be
+// claimed again from visibleAt on. A task out of attempts is dead-lettered
+// instead.
+func (s *Store) QueueNack(
+	queue, id, claimID string, visibleAt int64, reason string, index uint64,
+) (*Task, error) {
+	var task *Task
+
+	err := s.db.Update(func(tx *buntdb.Tx) error {
+		var err error
+		task, err = claimedTask(tx, queue, id, claimID)
+		if err != nil {
+			return err
+		}
+
+		current := *task
+		task.ClaimID = ""
+		task.LastError = reason
+		task.ModifyIndex = index
+		if task.Attempts >= task.MaxAttempts {
+			task.State = TaskDead
+			task.VisibleAt = 0
+		} else {
+			task.State = TaskPending
+			task.VisibleAt = visibleAt
+		}
+
+		if err := setJSON(tx, taskKey(queue, id), task); err != nil {
+			return err
+		}
+		if err := indexTask(tx, &current, task); err != nil {
+			return err
+		}
+		return setAppliedIndex(tx, index)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// QueueDelete removes a task in any state.
+func (s *Store) QueueDelete(queue, id string, index uint64) error {
+	return s.db.Update(func(tx *buntdb.Tx) error {
+		task, err := getTask(tx, queue, id)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Delete(taskKey(queue, id)); err != nil {
+			return err
+		}
+		if err := indexTask(tx, task, nil); err != nil {
+			return err
+		}
+		return setAppliedIndex(tx, index)
+	})
+}
+
+// QueueTasks returns the tasks of a queue in the given state, or all of them
+// when state is empty.
+func (s *Store) QueueTasks(queue, state string) ([]Task, error) {
+	var tasks []Task
+	err := s.db.View(func(tx *buntdb.Tx) error {
+		return listTasks(tx, queuePrefix+queue+"/", func(t *Task) {
+			if state == "" || t.State == state {
+				tasks = append(tasks, *t)
+			}
+		})
+	})
+
+	return tasks, err
+}
+
+// QueueList returns every queue that holds tasks.
+func (s *Store) QueueList() ([]QueueInfo, error) {
+	queues := make(map[string]*QueueInfo)
+	err := s.db.View(func(tx *buntdb.Tx) error {
+		return listTasks(tx, queuePrefix, func(t *Task) {
+			info, ok := queues[t.Queue]
+			if !ok {
+				info = &QueueInfo{Name: t.Queue}
+				queues[t.Queue] = info
+			}
+			switch t.State {
+			case TaskPending:
+				info.Pending++
+			case TaskClaimed:
+				info.Claimed++
+			case TaskDead:
+				info.Dead++
+			}
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]QueueInfo, 0, len(queues))
+	for _, info := range queues {
+		infos = append(infos, *info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+
+	return infos, nil
+}
+
+// ExpiredLeases returns the claimed tasks whose lease ran out before now.
+func (s *Store) ExpiredLeases(now time.Time) ([]Task, error) {
+	end := fmt.Sprintf("%s%020d", queueLeasePrefix, now.UnixNano())
+
+	var expired []Task
+	err := s.db.View(func(tx *buntdb.Tx) error {
+		var records []string
+		err := tx.AscendRange("", queueLeasePrefix, end, func(k, v string) bool {
+			records = append(records, k[len(queueLeasePrefix)+21:])
+			return true
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, record := range records {
+			queue, id, _ := strings.Cut(record, "/")
+			task, err := getTask(tx, queue, id)
+			if err != nil {
+				return err
+			}
+			expired = append(expired, *task)
+		}
+		return nil
+	})
+
+	return expired, err
+}
@@ -0,0 +1,168 @@
+package taskvault
+
+import (
+	"testing"
+	"time"
+
+	"github.com/danluki/taskvault/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_QueueClaimOrder(t *testing.T) {
+	s := newTestStore(t)
+	now := time.Now().UnixNano()
+
+	enqueue := func(id string, priority, visibleAt int64, index uint64) {
+		_, err := s.QueueEnqueue(&Task{
+			ID: id, Queue: "jobs", Priority: priority, VisibleAt: visibleAt, MaxAttempts: 3,
+		}, index)
+		require.NoError(t, err)
+	}
+	enqueue("low", 0, now, 1)
+	enqueue("high", 5, now, 2)
+	enqueue("high-later", 5, now, 3)
+	enqueue("delayed", 10, now+int64(time.Hour), 4)
+
+	var claimed []string
+	for i := uint64(0); i < 4; i++ {
+		task, err := s.QueueClaim("jobs", now, now+int64(time.Minute), "c", 5+i)
+		require.NoError(t, err)
+		if task == nil {
+			break
+		}
+		assert.Equal(t, TaskClaimed, task.State)
+		assert.Equal(t, uint32(1), task.Attempts)
+		claimed = append(claimed, task.ID)
+	}
+	assert.Equal(t, []string{"high", "high-later", "low"}, claimed)
+
+	pending, err := s.QueueTasks("jobs", TaskPending)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, "delayed", pending[0].ID)
+
+	queues, err := s.QueueList()
+	require.NoError(t, err)
+	assert.Equal(t, []QueueInfo{{Name: "jobs", Pending: 1, Claimed: 3}}, queues)
+}
+
+func TestStore_QueueClaimClock(t *testing.T) {
+	s := newTestStore(t)
+	now := time.Now().UnixNano()
+	later := now + int64(time.Second)
+
+	for i, task := range []*Task{
+		{ID: "first", Priority: 10, VisibleAt: now},
+		{ID: "later", Priority: 5, VisibleAt: later},
+		{ID: "negative", Priority: -5, VisibleAt: now},
+	} {
+		task.Queue, task.MaxAttempts = "jobs", 3
+		_, err := s.QueueEnqueue(task, uint64(i+1))
+		require.NoError(t, err)
+	}
+
+	claim := func(at int64, index uint64) string {
+		task, err := s.QueueClaim("jobs", at, at+int64(time.Minute), "c", index)
+		require.NoError(t, err)
+		if task == nil {
+			return ""
+		}
+		return task.ID
+	}
+	assert.Equal(t, "first", claim(later, 4))
+	// A leader with a clock behind the previous one does not see tasks
+	// that are not visible to it yet.
+	assert.Equal(t, "negative", claim(now, 5))
+	assert.Empty(t, claim(now, 6))
+	assert.Equal(t, "later", claim(later, 7))
+}
+
+func TestStore_QueueAckNack(t *testing.T) {
+	s := newTestStore(t)
+	now := time.Now().UnixNano()
+
+	_, err := s.QueueEnqueue(&Task{ID: "t", Queue: "jobs", MaxAttempts: 2}, 1)
+	require.NoError(t, err)
+
+	task, err := s.QueueClaim("jobs", now, now+int64(time.Minute), "first", 2)
+	require.NoError(t, err)
+	require.NotNil(t, task)
+
+	// Only the current claim can settle the task.
+	assert.ErrorIs(t, s.QueueAck("jobs", "t", "other", 3), ErrTaskNotClaimed)
+
+	task, err = s.QueueNack("jobs", "t", "first", now, "boom", 3)
+	require.NoError(t, err)
+	assert.Equal(t, TaskPending, task.State)
+	assert.Equal(t, "boom", task.LastError)
+	assert.ErrorIs(t, s.QueueAck("jobs", "t", "first", 4), ErrTaskNotClaimed)
+
+	task, err = s.QueueClaim("jobs", now, now+int64(time.Minute), "second", 4)
+	require.NoError(t, err)
+	require.NotNil(t, task)
+
+	// The second failed attempt dead-letters the task.
+	task, err = s.QueueNack("jobs", "t", "second", now, "boom again", 5)
+	require.NoError(t, err)
+	assert.Equal(t, TaskDead, task.State)
+
+	task, err = s.QueueClaim("jobs", now, now+int64(time.Minute), "third", 6)
+	require.NoError(t, err)
+	assert.Nil(t, task)
+
+	require.NoError(t, s.QueueDelete("jobs", "t", 7))
+	assert.ErrorIs(t, s.QueueDelete("jobs", "t", 8), ErrTaskNotFound)
+
+	// Tasks are hidden from clients.
+	pairs, err := s.GetAllValues()
+	require.NoError(t, err)
+	assert.Empty(t, pairs)
+}
+
+func TestStore_ExpiredLeases(t *testing.T) {
+	s := newTestStore(t)
+	now := time.Now()
+
+	for i, id := range []string{"a", "b"} {
+		_, err := s.QueueEnqueue(&Task{ID: id, Queue: "jobs", MaxAttempts: 1}, uint64(i+1))
+		require.NoError(t, err)
+	}
+	_, err := s.QueueClaim("jobs", now.UnixNano(), now.Add(-time.Second).UnixNano(), "old", 3)
+	require.NoError(t, err)
+	_, err = s.QueueClaim("jobs", now.UnixNano(), now.Add(time.Minute).UnixNano(), "new", 4)
+	require.NoError(t, err)
+
+	expired, err := s.ExpiredLeases(now)
+	require.NoError(t, err)
+	require.Len(t, expired, 1)
+	assert.Equal(t, "old", expired[0].ClaimID)
+}
+
+func TestFSM_QueueClaim(t *testing.T) {
+	fsm := newTestFSM(t)
+	now := time.Now().UnixNano()
+
+	res := applyCommand(t, fsm, 1, QueueEnqueueType, &types.Task{
+		Id: "t", Queue: "jobs", Payload: "work", VisibleAt: now, MaxAttempts: 3,
+	})
+	require.IsType(t, &Task{}, res)
+
+	res = applyCommand(t, fsm, 2, QueueClaimType, &types.QueueClaimRequest{
+		Queue: "jobs", VisibilityTimeout: 30, Now: now, ClaimId: "c",
+	})
+	task, ok := res.(*Task)
+	require.True(t, ok)
+	require.NotNil(t, task)
+	assert.Equal(t, "work", task.Payload)
+	assert.Equal(t, now+int64(30*time.Second), task.VisibleAt)
+
+	res = applyCommand(t, fsm, 3, QueueClaimType, &types.QueueClaimRequest{
+		Queue: "jobs", VisibilityTimeout: 30, Now: now, ClaimId: "d",
+	})
+	assert.Nil(t, res.(*Task))
+
+	assert.Nil(t, applyCommand(t, fsm, 4, QueueAckType, &types.QueueTaskRequest{
+		Queue: "jobs", Id: "t", ClaimId: "c",
+	}))
+}
@@ -1,15 +1,20 @@
 package taskvault
 
 import (
+	"crypto/tls"
+	"errors"
 	"net"
 	"time"
 
 	"github.com/hashicorp/raft"
+	"github.com/soheilhy/cmux"
 	"go.uber.org/zap"
 )
 
 type RaftLayer struct {
-	ln     net.Listener
+	ln  net.Listener
+	tls *tlsConfigurator
+
 	logger *zap.SugaredLogger
 }
 
@@ -19,8 +24,11 @@ func NewRaftLayer(logger *zap.SugaredLogger) *RaftLayer {
 	return &RaftLayer{logger: logger}
 }
 
-func NewTLSRaftLayer(logger *zap.SugaredLogger) *RaftLayer {
+// NewTLSRaftLayer returns a layer that dials other servers over TLS. The
+// listener it is opened with must already terminate TLS.
+func NewTLSRaftLayer(tlsConfig *tlsConfigurator, logger *zap.SugaredLogger) *RaftLayer {
 	return &RaftLayer{
+		tls:    tlsConfig,
 		logger: logger,
 	}
 }
@@ -35,14 +43,25 @@ func (t *RaftLayer) Dial(addr raft.ServerAddress, timeout time.Duration) (net.Co
 	var err error
 	var conn net.Conn
 
-	conn, err = dialer.Dial("tcp", string(addr))
+	if t.tls == nil {
+		conn, err = dialer.Dial("tcp", string(addr))
+		return conn, err
+	}
+
+	host, _, err := net.SplitHostPort(string(addr))
+	if err != nil {
+		return nil, err
+	}
+	conn, err = tls.DialWithDialer(dialer, "tcp", string(addr), t.tls.OutgoingConfig(host))
 
 	return conn, err
 }
 
 func (t *RaftLayer) Accept() (net.Conn, error) {
 	c, err := t.ln.Accept()
-	if err != nil {
+	// The listener is closed when the agent shuts down.
+	if err != nil && !errors.Is(err, cmux.ErrListenerClosed) &&
+		!errors.Is(err, cmux.ErrServerClosed) && !errors.Is(err, net.ErrClosed) {
 		t.logger.Error(err)
 	}
 
@@ -12,6 +12,10 @@ import (
 const (
 	StatusReap = serf.MemberStatus(-1)
 
+	// roleTag set to roleReplica marks servers that join as non-voters.
+	roleTag     = "role"
+	roleReplica = "replica"
+
 	maxPeerRetries = 6
 )
 
@@ -65,6 +69,9 @@ func (a *Agent) maybeBootstrap() {
 		if parts.Bootstrap {
 			return
 		}
+		if parts.NonVoter {
+			continue
+		}
 		voters++
 		servers = append(servers, *parts)
 	}
@@ -77,7 +84,7 @@ func (a *Agent) maybeBootstrap() {
 		var peers []string
 
 		for i := range maxPeerRetries {
-			configuration, err := a.GRPCClient.RaftGetConfiguration(server.RPCAddr.String())
+			configuration, err := a.serverClient().RaftGetConfiguration(server.RPCAddr.String())
 			if err != nil {
 				next := (1 << i) * time.Second
 				a.logger.Error(
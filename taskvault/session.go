@@ -0,0 +1,91 @@
+package taskvault
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/danluki/taskvault/pkg/types"
+	"github.com/hashicorp/serf/serf"
+)
+
+// Behaviors of a session, what happens to the keys it locks when it is
+// destroyed.
+const (
+	SessionRelease = "release"
+	SessionDelete  = "delete"
+)
+
+var (
+	ErrSessionNotFound = errors.New("session not found")
+	ErrLockConflict    = errors.New("lock conflict")
+
+	errAcquireAndRelease = errors.New("acquire and release can not be combined")
+)
+
+// Session ties locks to a client. It is destroyed when it is not renewed
+// within its TTL, when its node fails or leaves, or on request, and its
+// locks are freed with it.
+type Session struct {
+	ID   string
+	Name string
+	Node string
+	// TTL is in seconds, 0 for a session that only ends with its node.
+	TTL      int64
+	Behavior string
+	// ExpiresAt is the absolute expiry in unix nanoseconds, 0 for none.
+	ExpiresAt   int64
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+func sessionFromProto(s *types.Session) *Session {
+	return &Session{
+		ID:          s.Id,
+		Name:        s.Name,
+		Node:        s.Node,
+		TTL:         s.Ttl,
+		Behavior:    s.Behavior,
+		ExpiresAt:   s.ExpiresAt,
+		CreateIndex: s.CreateIndex,
+		ModifyIndex: s.ModifyIndex,
+	}
+}
+
+func (s *Session) proto() *types.Session {
+	return &types.Session{
+		Id:          s.ID,
+		Name:        s.Name,
+		Node:        s.Node,
+		Ttl:         s.TTL,
+		Behavior:    s.Behavior,
+		ExpiresAt:   s.ExpiresAt,
+		CreateIndex: s.CreateIndex,
+		ModifyIndex: s.ModifyIndex,
+	}
+}
+
+// validate fills in the default behavior and checks the session.
+func (s *Session) validate() error {
+	if s.Behavior == "" {
+		s.Behavior = SessionRelease
+	}
+	if s.Behavior != SessionRelease && s.Behavior != SessionDelete {
+		return fmt.Errorf("unknown session behavior %q", s.Behavior)
+	}
+	if s.TTL < 0 {
+		return errors.New("session ttl must not be negative")
+	}
+
+	return nil
+}
+
+// memberAlive reports whether node is an alive member of the cluster.
+func (a *Agent) memberAlive(node string) bool {
+	for _, m := range a.serf.Members() {
+		if m.Name == node {
+			return m.Status == serf.StatusAlive
+		}
+	}
+
+	return false
+}
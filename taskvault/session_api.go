@@ -0,0 +1,85 @@
+package taskvault
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *HTTPTransport) sessionListHandler(c *gin.Context) {
+	sessions, err := h.agent.Store.SessionList()
+	if err != nil {
+		h.renderSessionError(c, err)
+		return
+	}
+
+	if sessions == nil {
+		sessions = []Session{}
+	}
+	renderJSON(c, http.StatusOK, sessions)
+}
+
+func (h *HTTPTransport) sessionGetHandler(c *gin.Context) {
+	session, err := h.agent.Store.SessionGet(c.Param("id"))
+	if err != nil {
+		h.renderSessionError(c, err)
+		return
+	}
+
+	renderJSON(c, http.StatusOK, session)
+}
+
+// sessionCreateHandler creates a session from the Name, Node, TTL and
+// Behavior fields of the body. The session is tied to this node when the
+// body names none.
+func (h *HTTPTransport) sessionCreateHandler(c *gin.Context) {
+	body := &Session{}
+	if err := c.ShouldBindJSON(body); err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+	// The client calls the leader directly, it would default to its node.
+	if body.Node == "" {
+		body.Node = h.agent.config.NodeName
+	}
+
+	session, err := h.client(c).SessionCreate(body.proto())
+	if err != nil {
+		h.renderSessionError(c, err)
+		return
+	}
+
+	setIndexHeader(c, session.ModifyIndex)
+	renderJSON(c, http.StatusOK, session)
+}
+
+func (h *HTTPTransport) sessionRenewHandler(c *gin.Context) {
+	session, err := h.client(c).SessionRenew(c.Param("id"))
+	if err != nil {
+		h.renderSessionError(c, err)
+		return
+	}
+
+	setIndexHeader(c, session.ModifyIndex)
+	renderJSON(c, http.StatusOK, session)
+}
+
+// sessionDestroyHandler destroys a session, its locks are freed.
+func (h *HTTPTransport) sessionDestroyHandler(c *gin.Context) {
+	if err := h.client(c).SessionDestroy(c.Param("id")); err != nil {
+		h.renderSessionError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (h *HTTPTransport) renderSessionError(c *gin.Context, err error) {
+	if errors.Is(err, ErrSessionNotFound) {
+		_ = c.AbortWithError(http.StatusNotFound, err)
+		return
+	}
+
+	h.renderWriteError(c, err)
+}
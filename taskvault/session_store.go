@@ -0,0 +1,224 @@
+package taskvault
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tidwall/buntdb"
+)
+
+// Sessions live in the reserved key space next to the ACLs, so they are
+// replicated and snapshotted with the keys they lock.
+const sessionPrefix = reservedPrefix + "session/"
+
+func getSession(tx *buntdb.Tx, id string) (*Session, error) {
+	var session Session
+	if err := getJSON(tx, sessionPrefix+id, &session); err != nil {
+		if errors.Is(err, errRecordNotFound) {
+			return nil, ErrSessionNotFound
+		}
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// lockEntry applies the lock part of a write to e. Acquiring needs an
+// existing session and fails while another session holds the key,
+// releasing needs the key to be held by the session.
+func lockEntry(tx *buntdb.Tx, e *entry, opts WriteOptions) error {
+	switch {
+	case opts.Acquire != "":
+		if _, err := getSession(tx, opts.Acquire); err != nil {
+			return err
+		}
+		if e.Session == opts.Acquire {
+			return nil
+		}
+		if e.Session != "" {
+			return fmt.Errorf("%w: held by session %q", ErrLockConflict, e.Session)
+		}
+		e.Session = opts.Acquire
+		e.LockIndex++
+	case opts.Release != "":
+		if e.Session != opts.Release {
+			return fmt.Errorf("%w: not held by session %q", ErrLockConflict, opts.Release)
+		}
+		e.Session = ""
+	}
+
+	return nil
+}
+
+// SessionCreate stores a new session.
+func (s *Store) SessionCreate(session *Session, index uint64) (*Session, error) {
+	if session.ID == "" {
+		return nil, errors.New("session id is required")
+	}
+
+	stored := *session
+	stored.CreateIndex, stored.ModifyIndex = index, index
+
+	err := s.db.Update(func(tx *buntdb.Tx) error {
+		if _, err := tx.Get(sessionPrefix + stored.ID); err == nil {
+			return fmt.Errorf("session %q already exists", stored.ID)
+		} else if !errors.Is(err, buntdb.ErrNotFound) {
+			return err
+		}
+
+		if err := setJSON(tx, sessionPrefix+stored.ID, &stored); err != nil {
+			return err
+		}
+		return setAppliedIndex(tx, index)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &stored, nil
+}
+
+// SessionRenew extends the session to expiresAt.
+func (s *Store) SessionRenew(id string, expiresAt int64, index uint64) (*Session, error) {
+	var session *Session
+
+	err := s.db.Update(func(tx *buntdb.Tx) error {
+		var err error
+		session, err = getSession(tx, id)
+		if err != nil {
+			return err
+		}
+		session.ExpiresAt = expiresAt
+		session.ModifyIndex = index
+
+		if err := setJSON(tx, sessionPrefix+id, session); err != nil {
+			return err
+		}
+		return setAppliedIndex(tx, index)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// SessionDestroy removes a session along with its locks. Depending on the
+// behavior of the session, the locked keys are either released or deleted;
+// they are returned as such. A non nil cas must match the modify index of
+// the session.
+func (s *Store) SessionDestroy(
+	id string, cas *uint64, index uint64,
+) (released []Pair, deleted []Pair, err error) {
+	err = s.db.Update(func(tx *buntdb.Tx) error {
+		session, err := getSession(tx, id)
+		if err != nil {
+			return err
+		}
+		if cas != nil && *cas != session.ModifyIndex {
+			return fmt.Errorf(
+				"%w: session %q is at modify index %d", ErrCASConflict, id, session.ModifyIndex,
+			)
+		}
+
+		prefix := lockKey(id, "")
+		var keys []string
+		err = tx.AscendRange("", prefix, prefixEnd(prefix), func(k, v string) bool {
+			keys = append(keys, k[len(prefix):])
+			return true
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, key := range keys {
+			e, err := getEntry(tx, key)
+			if err != nil {
+				return err
+			}
+			if e == nil {
+				continue
+			}
+
+			if session.Behavior == SessionDelete {
+				if err := deleteEntry(tx, key, e); err != nil {
+					return err
+				}
+				deleted = append(deleted, Pair{Key: key, ModifyIndex: index})
+				continue
+			}
+
+			unlocked := *e
+			unlocked.Session = ""
+			unlocked.ModifyIndex = index
+			raw, err := encodeEntry(&unlocked)
+			if err != nil {
+				return err
+			}
+			if _, _, err := tx.Set(key, raw, nil); err != nil {
+				return err
+			}
+			if err := indexEntry(tx, key, e, &unlocked); err != nil {
+				return err
+			}
+			released = append(released, unlocked.pair(key))
+		}
+
+		if _, err := tx.Delete(sessionPrefix + id); err != nil {
+			return err
+		}
+		return setAppliedIndex(tx, index)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return released, deleted, nil
+}
+
+func (s *Store) SessionGet(id string) (*Session, error) {
+	var session *Session
+
+	err := s.db.View(func(tx *buntdb.Tx) error {
+		var err error
+		session, err = getSession(tx, id)
+		return err
+	})
+
+	return session, err
+}
+
+func (s *Store) SessionList() ([]Session, error) {
+	var sessions []Session
+	err := s.db.View(func(tx *buntdb.Tx) error {
+		return listJSON(tx, sessionPrefix, func(raw string) error {
+			var session Session
+			if err := json.Unmarshal([]byte(raw), &session); err != nil {
+				return err
+			}
+			sessions = append(sessions, session)
+			return nil
+		})
+	})
+
+	return sessions, err
+}
+
+// ExpiredSessions returns the sessions whose TTL ran out before now.
+func (s *Store) ExpiredSessions(now time.Time) ([]Session, error) {
+	sessions, err := s.SessionList()
+	if err != nil {
+		return nil, err
+	}
+
+	var expired []Session
+	for _, session := range sessions {
+		if session.ExpiresAt != 0 && session.ExpiresAt < now.UnixNano() {
+			expired = append(expired, session)
+		}
+	}
+
+	return expired, nil
+}
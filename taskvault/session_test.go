@@ -0,0 +1,162 @@
+package taskvault
+
+import (
+	"testing"
+	"time"
+
+	"github.com/danluki/taskvault/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_SessionLocks(t *testing.T) {
+	s := newTestStore(t)
+
+	_, err := s.SetValue("lock", "a", WriteOptions{Index: 1, Acquire: "missing"})
+	assert.ErrorIs(t, err, ErrSessionNotFound)
+	assert.NotErrorIs(t, err, ErrACLNotFound)
+
+	_, err = s.SessionCreate(&Session{ID: "s1", Behavior: SessionRelease}, 2)
+	require.NoError(t, err)
+	_, err = s.SessionCreate(&Session{ID: "s2", Behavior: SessionRelease}, 3)
+	require.NoError(t, err)
+
+	p, err := s.SetValue("lock", "a", WriteOptions{Index: 4, Acquire: "s1"})
+	require.NoError(t, err)
+	assert.Equal(t, "s1", p.Session)
+	assert.Equal(t, uint64(1), p.LockIndex)
+
+	// Acquiring again keeps the lock, another session is turned down.
+	p, err = s.SetValue("lock", "b", WriteOptions{Index: 5, Acquire: "s1"})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), p.LockIndex)
+	_, err = s.SetValue("lock", "c", WriteOptions{Index: 6, Acquire: "s2"})
+	assert.ErrorIs(t, err, ErrLockConflict)
+	_, err = s.UpdateValue("lock", "c", WriteOptions{Index: 6, Release: "s2"})
+	assert.ErrorIs(t, err, ErrLockConflict)
+
+	// Plain writes leave the lock alone.
+	p, err = s.UpdateValue("lock", "d", WriteOptions{Index: 7})
+	require.NoError(t, err)
+	assert.Equal(t, "s1", p.Session)
+
+	p, err = s.UpdateValue("lock", "e", WriteOptions{Index: 8, Release: "s1"})
+	require.NoError(t, err)
+	assert.Empty(t, p.Session)
+
+	p, err = s.SetValue("lock", "f", WriteOptions{Index: 9, Acquire: "s2"})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), p.LockIndex)
+
+	// Sessions are hidden from clients.
+	pairs, err := s.GetAllValues()
+	require.NoError(t, err)
+	assert.Len(t, pairs, 1)
+}
+
+func TestStore_SessionDestroy(t *testing.T) {
+	s := newTestStore(t)
+
+	_, err := s.SessionCreate(&Session{ID: "release", Behavior: SessionRelease}, 1)
+	require.NoError(t, err)
+	_, err = s.SessionCreate(&Session{ID: "delete", Behavior: SessionDelete}, 2)
+	require.NoError(t, err)
+	_, err = s.SetValue("a", "v", WriteOptions{Index: 3, Acquire: "release"})
+	require.NoError(t, err)
+	_, err = s.SetValue("b", "v", WriteOptions{Index: 4, Acquire: "delete"})
+	require.NoError(t, err)
+	_, err = s.SetValue("c", "v", WriteOptions{Index: 5})
+	require.NoError(t, err)
+
+	_, _, err = s.SessionDestroy("release", casIndex(7), 6)
+	assert.ErrorIs(t, err, ErrCASConflict)
+
+	released, deleted, err := s.SessionDestroy("release", casIndex(1), 6)
+	require.NoError(t, err)
+	require.Len(t, released, 1)
+	assert.Equal(t, "a", released[0].Key)
+	assert.Empty(t, deleted)
+
+	p, err := s.GetValue("a")
+	require.NoError(t, err)
+	assert.Empty(t, p.Session)
+	assert.Equal(t, uint64(6), p.ModifyIndex)
+
+	released, deleted, err = s.SessionDestroy("delete", nil, 7)
+	require.NoError(t, err)
+	assert.Empty(t, released)
+	require.Len(t, deleted, 1)
+	_, err = s.GetValue("b")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+
+	_, err = s.GetValue("c")
+	assert.NoError(t, err)
+	_, _, err = s.SessionDestroy("delete", nil, 8)
+	assert.ErrorIs(t, err, ErrSessionNotFound)
+}
+
+func TestStore_SessionLockRecords(t *testing.T) {
+	s := newTestStore(t)
+
+	_, err := s.SessionCreate(&Session{ID: "s1", Behavior: SessionRelease}, 1)
+	require.NoError(t, err)
+	for i, key := range []string{"held", "released", "deleted"} {
+		_, err = s.SetValue(key, "v", WriteOptions{Index: uint64(2 + i), Acquire: "s1"})
+		require.NoError(t, err)
+	}
+	_, err = s.UpdateValue("released", "v", WriteOptions{Index: 5, Release: "s1"})
+	require.NoError(t, err)
+	require.NoError(t, s.DeleteValue("deleted", WriteOptions{Index: 6}))
+
+	// Only the keys still locked are released.
+	released, _, err := s.SessionDestroy("s1", nil, 7)
+	require.NoError(t, err)
+	require.Len(t, released, 1)
+	assert.Equal(t, "held", released[0].Key)
+
+	p, err := s.GetValue("released")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), p.ModifyIndex)
+}
+
+func TestStore_ExpiredSessions(t *testing.T) {
+	s := newTestStore(t)
+	now := time.Now()
+
+	_, err := s.SessionCreate(&Session{ID: "old", ExpiresAt: now.Add(-time.Second).UnixNano()}, 1)
+	require.NoError(t, err)
+	_, err = s.SessionCreate(&Session{ID: "new", ExpiresAt: now.Add(time.Minute).UnixNano()}, 2)
+	require.NoError(t, err)
+	_, err = s.SessionCreate(&Session{ID: "forever"}, 3)
+	require.NoError(t, err)
+
+	expired, err := s.ExpiredSessions(now)
+	require.NoError(t, err)
+	require.Len(t, expired, 1)
+	assert.Equal(t, "old", expired[0].ID)
+
+	renewed, err := s.SessionRenew("old", now.Add(time.Minute).UnixNano(), 4)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), renewed.ModifyIndex)
+
+	expired, err = s.ExpiredSessions(now)
+	require.NoError(t, err)
+	assert.Empty(t, expired)
+}
+
+func TestFSM_SessionDestroyNotifiesWatchers(t *testing.T) {
+	fsm := newTestFSM(t)
+
+	applyCommand(t, fsm, 1, SessionCreateType, &types.Session{Id: "s", Behavior: SessionRelease})
+	res := applyCommand(t, fsm, 2, AddPairType, &types.CreateValueRequest{
+		Key:     "locks/job",
+		Acquire: "s",
+	})
+	require.IsType(t, &Pair{}, res)
+
+	w := fsm.watches.Watch("locks/", true)
+	defer fsm.watches.Stop(w)
+
+	assert.Nil(t, applyCommand(t, fsm, 3, SessionDestroyType, &types.SessionRequest{Id: "s"}))
+	assert.Equal(t, uint64(3), <-w.ch)
+}
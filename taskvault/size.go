@@ -0,0 +1,39 @@
+package taskvault
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrTooLarge = errors.New("too large")
+
+// SizeError is returned for a write whose key or value exceeds the limits
+// of the server, see Config.MaxKeySize and Config.MaxValueSize.
+type SizeError struct {
+	// Field is either "key" or "value".
+	Field string
+	Size  int
+	Limit int
+}
+
+func (e *SizeError) Error() string {
+	return fmt.Sprintf("%s of %d bytes exceeds the limit of %d", e.Field, e.Size, e.Limit)
+}
+
+func (e *SizeError) Unwrap() error {
+	return ErrTooLarge
+}
+
+// checkSize checks a key and value against the limits of the agent. Writes
+// are checked before they are forwarded or applied, oversized ones never
+// reach the Raft log.
+func (a *Agent) checkSize(key string, value []byte) error {
+	if limit := a.config.MaxKeySize; limit > 0 && len(key) > limit {
+		return &SizeError{Field: "key", Size: len(key), Limit: limit}
+	}
+	if limit := a.config.MaxValueSize; limit > 0 && len(value) > limit {
+		return &SizeError{Field: "value", Size: len(value), Limit: limit}
+	}
+
+	return nil
+}
@@ -0,0 +1,295 @@
+package taskvault
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/raft"
+)
+
+// Files of a snapshot archive, a gzipped tar holding the metadata, the
+// state as written by Store.Snapshot and the SHA-256 sums of both.
+const (
+	snapshotMetaFile  = "meta.json"
+	snapshotStateFile = "state.bin"
+	snapshotSumsFile  = "SHA256SUMS"
+
+	snapshotRestoreTimeout = time.Minute
+)
+
+var ErrSnapshotCorrupt = errors.New("snapshot archive is corrupt")
+
+// SnapshotMeta describes a snapshot archive.
+type SnapshotMeta struct {
+	ID    string
+	Index uint64
+	Term  uint64
+	// Version is the version of the agent that took the snapshot.
+	Version string
+	// Size is the size of the state in bytes.
+	Size int64
+}
+
+// WriteSnapshot writes a snapshot archive of state to w. meta.Size must be
+// the exact size of state.
+func WriteSnapshot(w io.Writer, meta *SnapshotMeta, state io.Reader) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	sums := make(map[string]string)
+
+	metaJSON, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+
+	add := func(name string, size int64, r io.Reader) error {
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    name,
+			Mode:    0o600,
+			Size:    size,
+			ModTime: time.Now(),
+		}); err != nil {
+			return err
+		}
+
+		h := sha256.New()
+		if _, err := io.Copy(tw, io.TeeReader(r, h)); err != nil {
+			return err
+		}
+		sums[name] = hex.EncodeToString(h.Sum(nil))
+
+		return nil
+	}
+
+	if err := add(snapshotMetaFile, int64(len(metaJSON)), strings.NewReader(string(metaJSON))); err != nil {
+		return err
+	}
+	if err := add(snapshotStateFile, meta.Size, state); err != nil {
+		return err
+	}
+
+	var sumsFile strings.Builder
+	for _, name := range []string{snapshotMetaFile, snapshotStateFile} {
+		fmt.Fprintf(&sumsFile, "%s  %s\n", sums[name], name)
+	}
+	if err := add(snapshotSumsFile, int64(sumsFile.Len()), strings.NewReader(sumsFile.String())); err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	return gz.Close()
+}
+
+// ReadSnapshot reads and verifies a snapshot archive. The state is spooled
+// to a temporary file, which is removed when the returned reader is closed.
+func ReadSnapshot(r io.Reader) (*SnapshotMeta, io.ReadCloser, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrSnapshotCorrupt, err)
+	}
+
+	state, err := os.CreateTemp("", "taskvault-snapshot-")
+	if err != nil {
+		return nil, nil, err
+	}
+	spool := &tempFile{File: state}
+
+	meta, err := readSnapshotArchive(tar.NewReader(gz), state)
+	if err != nil {
+		_ = spool.Close()
+		return nil, nil, err
+	}
+
+	if _, err := state.Seek(0, io.SeekStart); err != nil {
+		_ = spool.Close()
+		return nil, nil, err
+	}
+
+	return meta, spool, nil
+}
+
+func readSnapshotArchive(tr *tar.Reader, state io.Writer) (*SnapshotMeta, error) {
+	var (
+		meta     *SnapshotMeta
+		size     int64
+		expected map[string]string
+	)
+	hashes := map[string]hash.Hash{
+		snapshotMetaFile:  sha256.New(),
+		snapshotStateFile: sha256.New(),
+	}
+
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrSnapshotCorrupt, err)
+		}
+
+		switch hdr.Name {
+		case snapshotMetaFile:
+			meta = &SnapshotMeta{}
+			dec := json.NewDecoder(io.TeeReader(tr, hashes[snapshotMetaFile]))
+			if err := dec.Decode(meta); err != nil {
+				return nil, fmt.Errorf("%w: %s", ErrSnapshotCorrupt, err)
+			}
+			// Hash whatever follows the JSON document as well.
+			if _, err := io.Copy(hashes[snapshotMetaFile], dec.Buffered()); err != nil {
+				return nil, err
+			}
+			if _, err := io.Copy(hashes[snapshotMetaFile], tr); err != nil {
+				return nil, fmt.Errorf("%w: %s", ErrSnapshotCorrupt, err)
+			}
+		case snapshotStateFile:
+			size, err = io.Copy(io.MultiWriter(state, hashes[snapshotStateFile]), tr)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s", ErrSnapshotCorrupt, err)
+			}
+		case snapshotSumsFile:
+			expected, err = readSums(tr)
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("%w: unexpected file %q", ErrSnapshotCorrupt, hdr.Name)
+		}
+	}
+
+	if meta == nil || expected == nil {
+		return nil, fmt.Errorf("%w: missing %s or %s", ErrSnapshotCorrupt, snapshotMetaFile, snapshotSumsFile)
+	}
+	for name, h := range hashes {
+		if sum := hex.EncodeToString(h.Sum(nil)); expected[name] != sum {
+			return nil, fmt.Errorf("%w: checksum mismatch for %s", ErrSnapshotCorrupt, name)
+		}
+	}
+	if size != meta.Size {
+		return nil, fmt.Errorf(
+			"%w: state is %d bytes, expected %d", ErrSnapshotCorrupt, size, meta.Size,
+		)
+	}
+
+	return meta, nil
+}
+
+func readSums(r io.Reader) (map[string]string, error) {
+	sums := make(map[string]string)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		sum, name, ok := strings.Cut(scanner.Text(), "  ")
+		if !ok {
+			return nil, fmt.Errorf("%w: malformed %s", ErrSnapshotCorrupt, snapshotSumsFile)
+		}
+		sums[name] = sum
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrSnapshotCorrupt, err)
+	}
+
+	return sums, nil
+}
+
+type tempFile struct {
+	*os.File
+}
+
+func (f *tempFile) Close() error {
+	err := f.File.Close()
+	_ = os.Remove(f.Name())
+	return err
+}
+
+// saveSnapshot writes a snapshot archive of the current state to w, calling
+// onMeta with its metadata first. On the leader a barrier makes sure every
+// committed write is included.
+func (a *Agent) saveSnapshot(w io.Writer, onMeta func(*SnapshotMeta)) error {
+	if a.IsLeader() {
+		if err := a.raft.Barrier(barrierWriteTimeout).Error(); err != nil {
+			return err
+		}
+	}
+
+	raftMeta, state, err := a.openSnapshot()
+	if err != nil {
+		return err
+	}
+	defer state.Close()
+
+	m := &SnapshotMeta{
+		ID:      raftMeta.ID,
+		Index:   raftMeta.Index,
+		Term:    raftMeta.Term,
+		Version: Version,
+		Size:    raftMeta.Size,
+	}
+	if onMeta != nil {
+		onMeta(m)
+	}
+
+	return WriteSnapshot(w, m, state)
+}
+
+// openSnapshot takes a new Raft snapshot, or opens the latest one when
+// nothing was applied since it was taken.
+func (a *Agent) openSnapshot() (*raft.SnapshotMeta, io.ReadCloser, error) {
+	future := a.raft.Snapshot()
+	err := future.Error()
+	if err == nil {
+		return future.Open()
+	}
+	if !errors.Is(err, raft.ErrNothingNewToSnapshot) {
+		return nil, nil, err
+	}
+
+	snaps, err := a.snapshots.List()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(snaps) == 0 {
+		return nil, nil, fmt.Errorf("no snapshot available")
+	}
+
+	return a.snapshots.Open(snaps[0].ID)
+}
+
+// restoreSnapshot replaces the state of the whole cluster with a snapshot
+// archive. It must run on the leader, which installs the snapshot on the
+// followers.
+func (a *Agent) restoreSnapshot(r io.Reader) (*SnapshotMeta, error) {
+	meta, state, err := ReadSnapshot(r)
+	if err != nil {
+		return nil, err
+	}
+	defer state.Close()
+
+	err = a.raft.Restore(&raft.SnapshotMeta{
+		Version: raft.SnapshotVersionMax,
+		ID:      meta.ID,
+		Index:   meta.Index,
+		Term:    meta.Term,
+		Size:    meta.Size,
+	}, state, snapshotRestoreTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return meta, nil
+}
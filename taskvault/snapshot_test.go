@@ -0,0 +1,72 @@
+package taskvault
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotArchive(t *testing.T) {
+	state := "snapshot state"
+	meta := &SnapshotMeta{ID: "1-2-3", Index: 2, Term: 1, Version: "devel", Size: int64(len(state))}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteSnapshot(&buf, meta, strings.NewReader(state)))
+
+	read, r, err := ReadSnapshot(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	defer r.Close()
+	assert.Equal(t, meta, read)
+
+	got, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, state, string(got))
+
+	_, _, err = ReadSnapshot(bytes.NewReader(buf.Bytes()[:buf.Len()/2]))
+	assert.ErrorIs(t, err, ErrSnapshotCorrupt)
+}
+
+func TestSnapshotArchive_Tampered(t *testing.T) {
+	state := "snapshot state"
+	meta := &SnapshotMeta{Index: 2, Size: int64(len(state))}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteSnapshot(&buf, meta, strings.NewReader(state)))
+
+	// Rewrite the archive with a different state of the same size.
+	gz, err := gzip.NewReader(&buf)
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+
+	var tampered bytes.Buffer
+	gzw := gzip.NewWriter(&tampered)
+	tw := tar.NewWriter(gzw)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		if hdr.Name == snapshotStateFile {
+			body = []byte(strings.ToUpper(state))
+		}
+		require.NoError(t, tw.WriteHeader(hdr))
+		_, err = tw.Write(body)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gzw.Close())
+
+	_, _, err = ReadSnapshot(&tampered)
+	assert.ErrorIs(t, err, ErrSnapshotCorrupt)
+	assert.ErrorContains(t, err, snapshotStateFile)
+}
@@ -2,16 +2,51 @@ package taskvault
 
 import (
 	"io"
+	"time"
 
 	"github.com/hashicorp/raft"
 )
 
 type SyncraStorage interface {
-	GetValue(key string) (string, error)
-	UpdateValue(key string, value string) error
-	SetValue(key string, value string) error
-	DeleteValue(key string) error
+	GetValue(key string) (*Pair, error)
+	UpdateValue(key string, value string, opts WriteOptions) (*Pair, error)
+	SetValue(key string, value string, opts WriteOptions) (*Pair, error)
+	DeleteValue(key string, opts WriteOptions) error
+	IncrValue(key string, opts IncrOptions) (*Pair, error)
+	Txn(ops []TxnOp, index uint64) ([]Pair, error)
 	GetAllValues() ([]Pair, error)
+	ListValues(opts ListOptions) ([]Pair, int, error)
+	ExpiredValues(now time.Time) ([]Pair, error)
+	AppliedIndex() (uint64, error)
+	ACLBootstrap(token *ACLToken, index uint64) (*ACLToken, error)
+	ACLSetToken(token *ACLToken, index uint64) (*ACLToken, error)
+	ACLDeleteToken(accessor string, index uint64) error
+	ACLGetToken(accessor string) (*ACLToken, error)
+	ACLTokenBySecret(secret string) (*ACLToken, error)
+	ACLListTokens() ([]ACLToken, error)
+	ACLSetPolicy(policy *ACLPolicy, index uint64) (*ACLPolicy, error)
+	ACLDeletePolicy(name string, index uint64) error
+	ACLGetPolicy(name string) (*ACLPolicy, error)
+	ACLListPolicies() ([]ACLPolicy, error)
+	SessionCreate(session *Session, index uint64) (*Session, error)
+	SessionRenew(id string, expiresAt int64, index uint64) (*Session, error)
+	SessionDestroy(id string, cas *uint64, index uint64) ([]Pair, []Pair, error)
+	SessionGet(id string) (*Session, error)
+	SessionList() ([]Session, error)
+	ExpiredSessions(now time.Time) ([]Session, error)
+	QueueEnqueue(task *Task, index uint64) (*Task, error)
+	QueueClaim(queue string, now, visibleUntil int64, claimID string, index uint64) (*Task, error)
+	QueueAck(queue, id, claimID string, index uint64) error
+	QueueNack(queue, id, claimID string, visibleAt int64, reason string, index uint64) (*Task, error)
+	QueueDelete(queue, id string, index uint64) error
+	QueueTasks(queue, state string) ([]Task, error)
+	QueueList() ([]QueueInfo, error)
+	ExpiredLeases(now time.Time) ([]Task, error)
+	JobSet(job *Job, index uint64) (*Job, error)
+	JobDelete(name string, index uint64) error
+	JobRun(name string, scheduledAt, nextRunAt int64, skip bool, taskID string, now int64, index uint64) (*Job, *Pair, error)
+	JobGet(name string) (*Job, error)
+	JobList() ([]Job, error)
 	Shutdown() error
 	Snapshot(w io.WriteCloser) error
 	Restore(r io.ReadCloser) error
@@ -1,12 +1,163 @@
 package taskvault
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/tidwall/buntdb"
 	"go.uber.org/zap"
 )
 
+var (
+	ErrKeyNotFound  = errors.New("key not found")
+	ErrCASConflict  = errors.New("cas conflict")
+	ErrReservedKey  = errors.New("key is reserved")
+	errEntryCorrupt = errors.New("corrupt entry")
+)
+
+// entryPrefix marks values written in the entry format. Values without it
+// were written by older versions and are read back as plain strings.
+const entryPrefix = "\x00"
+
+// reservedPrefix starts the keys the store keeps for itself. They are hidden
+// from reads and listings and can not be written by clients.
+const reservedPrefix = "\x00"
+
+// appliedIndexKey holds the last Raft index written to the store. It is
+// updated in the same transaction as the data so a durable store knows
+// which log entries it already holds after a restart.
+const appliedIndexKey = reservedPrefix + "applied_index"
+
+// Sync policies of the file backend, see NewFileStore.
+const (
+	SyncAlways      = "always"
+	SyncEverySecond = "everysecond"
+	SyncNever       = "never"
+)
+
+// CASError is returned when a check-and-set write is rejected because the
+// key was modified after the index the client based its write on.
+type CASError struct {
+	Key         string
+	ModifyIndex uint64
+}
+
+func (e *CASError) Error() string {
+	return fmt.Sprintf(
+		"%s: key %q is at modify index %d", ErrCASConflict, e.Key, e.ModifyIndex,
+	)
+}
+
+func (e *CASError) Unwrap() error {
+	return ErrCASConflict
+}
+
+// WriteOptions carries the Raft metadata of a write down to the store.
+type WriteOptions struct {
+	// Index is the Raft log index the write was committed at.
+	Index uint64
+	// CAS makes the write conditional on the key's current modify index.
+	// A CAS of 0 requires the key to be absent.
+	CAS *uint64
+	// ExpiresAt is the absolute expiry in unix nanoseconds, 0 for none.
+	ExpiresAt int64
+	// Acquire takes the lock of the key for this session, see lockEntry.
+	Acquire string
+	// Release gives up the lock of the key held by this session.
+	Release string
+	// Meta replaces the metadata of the key, nil keeps the current one.
+	Meta *KeyMeta
+}
+
+type entry struct {
+	Value string `json:"v"`
+	// Binary holds values that are not valid UTF-8 in place of Value, JSON
+	// strings would mangle them.
+	Binary      []byte            `json:"b,omitempty"`
+	CreateIndex uint64            `json:"ci"`
+	ModifyIndex uint64            `json:"mi"`
+	ExpiresAt   int64             `json:"ex,omitempty"`
+	Session     string            `json:"s,omitempty"`
+	LockIndex   uint64            `json:"li,omitempty"`
+	Flags       uint64            `json:"f,omitempty"`
+	ContentType string            `json:"ct,omitempty"`
+	Labels      map[string]string `json:"l,omitempty"`
+}
+
+func encodeEntry(e *entry) (string, error) {
+	if !utf8.ValidString(e.Value) {
+		binary := *e
+		binary.Value, binary.Binary = "", []byte(e.Value)
+		e = &binary
+	}
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		return "", err
+	}
+
+	return entryPrefix + string(b), nil
+}
+
+func decodeEntry(raw string) (*entry, error) {
+	if !strings.HasPrefix(raw, entryPrefix) {
+		return &entry{Value: raw}, nil
+	}
+
+	e := &entry{}
+	if err := json.Unmarshal([]byte(raw[len(entryPrefix):]), e); err != nil {
+		return nil, fmt.Errorf("%w: %s", errEntryCorrupt, err)
+	}
+	if e.Binary != nil {
+		e.Value, e.Binary = string(e.Binary), nil
+	}
+
+	return e, nil
+}
+
+func (e *entry) pair(key string) Pair {
+	return Pair{
+		Key:         key,
+		Value:       e.Value,
+		CreateIndex: e.CreateIndex,
+		ModifyIndex: e.ModifyIndex,
+		TTL:         e.remainingTTL(time.Now()),
+		Session:     e.Session,
+		LockIndex:   e.LockIndex,
+		Meta:        e.meta(),
+	}
+}
+
+// meta returns the metadata of the entry, nil when it has none.
+func (e *entry) meta() *KeyMeta {
+	if e.Flags == 0 && e.ContentType == "" && len(e.Labels) == 0 {
+		return nil
+	}
+
+	return &KeyMeta{Flags: e.Flags, ContentType: e.ContentType, Labels: e.Labels}
+}
+
+// remainingTTL returns the seconds left before the entry expires, rounded up.
+// Expired entries report 0 until the leader removes them.
+func (e *entry) remainingTTL(now time.Time) int64 {
+	if e.ExpiresAt == 0 {
+		return 0
+	}
+
+	left := time.Unix(0, e.ExpiresAt).Sub(now)
+	if left <= 0 {
+		return 0
+	}
+
+	return int64((left + time.Second - 1) / time.Second)
+}
+
 type Store struct {
 	db *buntdb.DB
 
@@ -15,10 +166,112 @@ type Store struct {
 
 var _ SyncraStorage = (*Store)(nil)
 
-func (s *Store) DeleteValue(key string) error {
+func isReservedKey(key string) bool {
+	return strings.HasPrefix(key, reservedPrefix)
+}
+
+// setAppliedIndex records index as applied, it must be called in the
+// transaction that wrote the data of the log entry.
+func setAppliedIndex(tx *buntdb.Tx, index uint64) error {
+	if index == 0 {
+		return nil
+	}
+
+	_, _, err := tx.Set(appliedIndexKey, strconv.FormatUint(index, 10), nil)
+	return err
+}
+
+// getEntry returns the entry stored under key, or nil if there is none.
+func getEntry(tx *buntdb.Tx, key string) (*entry, error) {
+	raw, err := tx.Get(key)
+	if err != nil {
+		if errors.Is(err, buntdb.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return decodeEntry(raw)
+}
+
+func checkCAS(key string, current *entry, cas *uint64) error {
+	if cas == nil {
+		return nil
+	}
+
+	var index uint64
+	if current != nil {
+		index = current.ModifyIndex
+	}
+
+	if *cas == 0 && current == nil {
+		return nil
+	}
+	if *cas != 0 && current != nil && *cas == index {
+		return nil
+	}
+
+	return &CASError{Key: key, ModifyIndex: index}
+}
+
+func putEntry(
+	tx *buntdb.Tx, key string, current *entry, value string, opts WriteOptions,
+) (*entry, error) {
+	e := &entry{
+		Value:       value,
+		CreateIndex: opts.Index,
+		ModifyIndex: opts.Index,
+		ExpiresAt:   opts.ExpiresAt,
+	}
+	if current != nil {
+		e.CreateIndex = current.CreateIndex
+		e.Session = current.Session
+		e.LockIndex = current.LockIndex
+		e.Flags, e.ContentType, e.Labels = current.Flags, current.ContentType, current.Labels
+	}
+	if m := opts.Meta; m != nil {
+		e.Flags, e.ContentType, e.Labels = m.Flags, m.ContentType, m.Labels
+	}
+	if err := lockEntry(tx, e, opts); err != nil {
+		return nil, err
+	}
+
+	raw, err := encodeEntry(e)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, _, err := tx.Set(key, raw, nil); err != nil {
+		return nil, err
+	}
+	if err := indexEntry(tx, key, current, e); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+func (s *Store) DeleteValue(key string, opts WriteOptions) error {
+	if isReservedKey(key) {
+		return ErrReservedKey
+	}
+
 	err := s.db.Update(func(tx *buntdb.Tx) error {
-		_, err := tx.Delete(key)
-		return err
+		current, err := getEntry(tx, key)
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			return ErrKeyNotFound
+		}
+		if err := checkCAS(key, current, opts.CAS); err != nil {
+			return err
+		}
+
+		if err := deleteEntry(tx, key, current); err != nil {
+			return err
+		}
+		return setAppliedIndex(tx, opts.Index)
 	})
 
 	return err
@@ -28,48 +281,307 @@ func (s *Store) GetAllValues() ([]Pair, error) {
 	var pairs []Pair
 
 	err := s.db.View(func(tx *buntdb.Tx) error {
+		var decodeErr error
 		err := tx.Ascend("", func(k, v string) bool {
-			pairs = append(pairs, Pair{
-				Key:   k,
-				Value: v,
+			if isReservedKey(k) {
+				return true
+			}
+			e, err := decodeEntry(v)
+			if err != nil {
+				decodeErr = err
+				return false
+			}
+			pairs = append(pairs, e.pair(k))
+			return true
+		})
+		if err != nil {
+			return err
+		}
+
+		return decodeErr
+	})
+
+	return pairs, err
+}
+
+// ListOptions selects a range of keys for ListValues.
+type ListOptions struct {
+	Prefix string
+	// StartKey is the inclusive lower bound, EndKey the exclusive upper one.
+	StartKey string
+	EndKey   string
+	KeysOnly bool
+	// Separator collapses keys sharing a path segment after Prefix into a
+	// single key ending in the separator. It implies KeysOnly.
+	Separator string
+	// Offset and Limit page through the matches, a Limit of 0 means no limit.
+	Offset int
+	Limit  int
+	// Filter, when set, hides the keys it returns false for. They are not
+	// counted either.
+	Filter func(key string) bool
+}
+
+// ListValues returns the pairs matching opts along with the total number of
+// matches. Only the requested page is decoded.
+func (s *Store) ListValues(opts ListOptions) ([]Pair, int, error) {
+	if opts.Separator != "" {
+		opts.KeysOnly = true
+	}
+
+	pivot := opts.Prefix
+	if opts.StartKey > pivot {
+		pivot = opts.StartKey
+	}
+
+	var (
+		pairs []Pair
+		total int
+	)
+
+	err := s.db.View(func(tx *buntdb.Tx) error {
+		var iterErr error
+		emit := func(k, v string) bool {
+			total++
+			if total <= opts.Offset || (opts.Limit > 0 && len(pairs) >= opts.Limit) {
+				return true
+			}
+			if opts.KeysOnly {
+				pairs = append(pairs, Pair{Key: k})
+				return true
+			}
+
+			e, err := decodeEntry(v)
+			if err != nil {
+				iterErr = err
+				return false
+			}
+			pairs = append(pairs, e.pair(k))
+
+			return true
+		}
+
+		for {
+			var folder string
+			err := tx.AscendGreaterOrEqual("", pivot, func(k, v string) bool {
+				if isReservedKey(k) {
+					return true
+				}
+				if !strings.HasPrefix(k, opts.Prefix) {
+					return false
+				}
+				if opts.EndKey != "" && k >= opts.EndKey {
+					return false
+				}
+				if opts.Filter != nil && !opts.Filter(k) {
+					return true
+				}
+
+				if opts.Separator != "" {
+					rest := k[len(opts.Prefix):]
+					if i := strings.Index(rest, opts.Separator); i >= 0 {
+						folder = k[:len(opts.Prefix)+i+len(opts.Separator)]
+						return false
+					}
+				}
+
+				return emit(k, v)
 			})
+			if err != nil {
+				return err
+			}
+			if iterErr != nil || folder == "" {
+				return iterErr
+			}
+
+			// Skip everything below the folder by seeking past it.
+			if !emit(folder, "") {
+				return iterErr
+			}
+			pivot = prefixEnd(folder)
+			if pivot == "" {
+				return nil
+			}
+		}
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return pairs, total, nil
+}
+
+// prefixEnd returns the smallest key greater than every key starting with
+// prefix, or an empty string if there is none.
+func prefixEnd(prefix string) string {
+	b := []byte(prefix)
+	for i := len(b) - 1; i >= 0; i-- {
+		if b[i] < 0xff {
+			b[i]++
+			return string(b[:i+1])
+		}
+	}
+
+	return ""
+}
+
+// ExpiredValues returns the pairs whose expiry is before now.
+func (s *Store) ExpiredValues(now time.Time) ([]Pair, error) {
+	end := fmt.Sprintf("%s%020d", ttlPrefix, now.UnixNano())
+
+	var pairs []Pair
+	err := s.db.View(func(tx *buntdb.Tx) error {
+		var keys []string
+		err := tx.AscendRange("", ttlPrefix, end, func(k, v string) bool {
+			keys = append(keys, ttlRecordKey(k))
 			return true
 		})
+		if err != nil {
+			return err
+		}
 
-		return err
+		for _, key := range keys {
+			e, err := getEntry(tx, key)
+			if err == nil && e != nil {
+				pairs = append(pairs, e.pair(key))
+			}
+		}
+		return nil
 	})
 
 	return pairs, err
 }
 
-func (s *Store) GetValue(key string) (string, error) {
-	var value string
+func (s *Store) GetValue(key string) (*Pair, error) {
+	if isReservedKey(key) {
+		return nil, ErrKeyNotFound
+	}
+
+	var pair Pair
 
 	err := s.db.View(func(tx *buntdb.Tx) error {
-		v, err := tx.Get(key)
+		e, err := getEntry(tx, key)
 		if err != nil {
 			return err
 		}
+		if e == nil {
+			return ErrKeyNotFound
+		}
 
-		value = v
+		pair = e.pair(key)
 
 		return nil
 	})
+	if err != nil {
+		return nil, err
+	}
 
-	return value, err
+	return &pair, nil
 }
 
+// AppliedIndex returns the last Raft index written to the store, 0 for an
+// empty store.
+func (s *Store) AppliedIndex() (uint64, error) {
+	var index uint64
+
+	err := s.db.View(func(tx *buntdb.Tx) error {
+		v, err := tx.Get(appliedIndexKey)
+		if err != nil {
+			if errors.Is(err, buntdb.ErrNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		index, err = strconv.ParseUint(v, 10, 64)
+		return err
+	})
+
+	return index, err
+}
+
+// Restore replaces the content of the store with a snapshot. The data is
+// swapped in a single transaction, and the applied index is only written
+// after it so that a restore interrupted by a crash leaves a durable store
+// that reports itself as empty and is rebuilt on the next start.
 func (s *Store) Restore(r io.ReadCloser) error {
-	return s.db.Load(r)
+	snap, err := buntdb.Open(":memory:")
+	if err != nil {
+		return err
+	}
+	defer snap.Close()
+
+	if err := snap.Load(r); err != nil {
+		return err
+	}
+
+	var applied string
+	err = s.db.Update(func(tx *buntdb.Tx) error {
+		if err := tx.DeleteAll(); err != nil {
+			return err
+		}
+
+		err := snap.View(func(stx *buntdb.Tx) error {
+			var setErr error
+			err := stx.Ascend("", func(k, v string) bool {
+				if k == appliedIndexKey {
+					applied = v
+					return true
+				}
+				_, _, setErr = tx.Set(k, v, nil)
+				return setErr == nil
+			})
+			if err != nil {
+				return err
+			}
+			return setErr
+		})
+		if err != nil {
+			return err
+		}
+
+		// Snapshots taken by older versions lack the current records.
+		return ensureIndex(tx)
+	})
+	if err != nil || applied == "" {
+		return err
+	}
+
+	return s.db.Update(func(tx *buntdb.Tx) error {
+		_, _, err := tx.Set(appliedIndexKey, applied, nil)
+		return err
+	})
 }
 
-func (s *Store) SetValue(key string, value string) error {
+func (s *Store) SetValue(key string, value string, opts WriteOptions) (*Pair, error) {
+	if isReservedKey(key) {
+		return nil, ErrReservedKey
+	}
+
+	var pair Pair
+
 	err := s.db.Update(func(tx *buntdb.Tx) error {
-		_, _, err := tx.Set(key, value, nil)
-		return err
+		current, err := getEntry(tx, key)
+		if err != nil {
+			return err
+		}
+		if err := checkCAS(key, current, opts.CAS); err != nil {
+			return err
+		}
+
+		e, err := putEntry(tx, key, current, value, opts)
+		if err != nil {
+			return err
+		}
+		pair = e.pair(key)
+
+		return setAppliedIndex(tx, opts.Index)
 	})
+	if err != nil {
+		return nil, err
+	}
 
-	return err
+	return &pair, nil
 }
 
 func (s *Store) Shutdown() error {
@@ -80,11 +592,113 @@ func (s *Store) Snapshot(w io.WriteCloser) error {
 	return s.db.Save(w)
 }
 
-func (s *Store) UpdateValue(key string, value string) error {
-	return s.SetValue(key, value)
+func (s *Store) UpdateValue(key string, value string, opts WriteOptions) (*Pair, error) {
+	if isReservedKey(key) {
+		return nil, ErrReservedKey
+	}
+
+	var pair Pair
+
+	err := s.db.Update(func(tx *buntdb.Tx) error {
+		current, err := getEntry(tx, key)
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			return ErrKeyNotFound
+		}
+		if err := checkCAS(key, current, opts.CAS); err != nil {
+			return err
+		}
+
+		e, err := putEntry(tx, key, current, value, opts)
+		if err != nil {
+			return err
+		}
+		pair = e.pair(key)
+
+		return setAppliedIndex(tx, opts.Index)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pair, nil
 }
 
-var _ SyncraStorage = (*Store)(nil)
+// Txn applies ops atomically at the given index. Results line up with ops and
+// are filled in for gets, sets and deletes of existing keys. When an operation fails nothing is
+// written and a *TxnError naming the operation is returned.
+func (s *Store) Txn(ops []TxnOp, index uint64) ([]Pair, error) {
+	results := make([]Pair, len(ops))
+
+	err := s.db.Update(func(tx *buntdb.Tx) error {
+		for i, op := range ops {
+			res, err := applyTxnOp(tx, op, index)
+			if err != nil {
+				return &TxnError{OpIndex: i, Err: err}
+			}
+			if res != nil {
+				results[i] = *res
+			}
+		}
+
+		return setAppliedIndex(tx, index)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func applyTxnOp(tx *buntdb.Tx, op TxnOp, index uint64) (*Pair, error) {
+	if isReservedKey(op.Key) {
+		return nil, ErrReservedKey
+	}
+
+	current, err := getEntry(tx, op.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Type {
+	case TxnGet:
+		if current == nil {
+			return nil, ErrKeyNotFound
+		}
+		pair := current.pair(op.Key)
+		return &pair, nil
+	case TxnSet:
+		e, err := putEntry(tx, op.Key, current, op.Value, WriteOptions{
+			Index:     index,
+			ExpiresAt: op.ExpiresAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+		pair := e.pair(op.Key)
+		return &pair, nil
+	case TxnDelete:
+		if current == nil {
+			return nil, nil
+		}
+		if err := deleteEntry(tx, op.Key, current); err != nil {
+			return nil, err
+		}
+		pair := current.pair(op.Key)
+		return &pair, nil
+	case TxnCheckIndex:
+		return nil, checkCAS(op.Key, current, &op.Index)
+	case TxnCheckExists:
+		if current == nil {
+			return nil, ErrKeyNotFound
+		}
+		return nil, nil
+	}
+
+	return nil, fmt.Errorf("unknown txn op type %d", op.Type)
+}
 
 func NewStore(logger *zap.SugaredLogger) (*Store, error) {
 	db, err := buntdb.Open(":memory:")
@@ -92,6 +706,44 @@ func NewStore(logger *zap.SugaredLogger) (*Store, error) {
 		return nil, err
 	}
 
+	return newStore(db, logger)
+}
+
+// NewFileStore opens a store that persists to the append only file at path.
+// sync is one of SyncAlways, SyncEverySecond or SyncNever and controls how
+// often writes are flushed to disk. Writes lost to a crash are applied again
+// from the Raft log on the next start.
+func NewFileStore(path string, sync string, logger *zap.SugaredLogger) (*Store, error) {
+	policy, err := parseSyncPolicy(sync)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := buntdb.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var config buntdb.Config
+	if err := db.ReadConfig(&config); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	config.SyncPolicy = policy
+	if err := db.SetConfig(config); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return newStore(db, logger)
+}
+
+func newStore(db *buntdb.DB, logger *zap.SugaredLogger) (*Store, error) {
+	if err := db.Update(ensureIndex); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
 	store := &Store{
 		db:     db,
 		logger: logger,
@@ -99,3 +751,16 @@ func NewStore(logger *zap.SugaredLogger) (*Store, error) {
 
 	return store, nil
 }
+
+func parseSyncPolicy(sync string) (buntdb.SyncPolicy, error) {
+	switch sync {
+	case SyncAlways:
+		return buntdb.Always, nil
+	case SyncEverySecond, "":
+		return buntdb.EverySecond, nil
+	case SyncNever:
+		return buntdb.Never, nil
+	}
+
+	return 0, fmt.Errorf("unknown store sync policy %q", sync)
+}
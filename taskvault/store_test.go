@@ -0,0 +1,410 @@
+package taskvault
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/buntdb"
+	"go.uber.org/zap"
+)
+
+func newTestStore(t *testing.T) *Store {
+	s, err := NewStore(zap.NewNop().Sugar())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Shutdown() })
+
+	return s
+}
+
+func casIndex(i uint64) *uint64 {
+	return &i
+}
+
+func TestStore_UpdateValue(t *testing.T) {
+	s := newTestStore(t)
+
+	_, err := s.UpdateValue("missing", "value", WriteOptions{Index: 1})
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+
+	_, err = s.GetValue("missing")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+
+	_, err = s.SetValue("key", "old", WriteOptions{Index: 2})
+	require.NoError(t, err)
+	_, err = s.UpdateValue("key", "new", WriteOptions{Index: 3})
+	require.NoError(t, err)
+
+	p, err := s.GetValue("key")
+	require.NoError(t, err)
+	assert.Equal(t, "new", p.Value)
+	assert.Equal(t, uint64(2), p.CreateIndex)
+	assert.Equal(t, uint64(3), p.ModifyIndex)
+}
+
+func TestStore_CAS(t *testing.T) {
+	s := newTestStore(t)
+
+	p, err := s.SetValue("key", "a", WriteOptions{Index: 5, CAS: casIndex(0)})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), p.ModifyIndex)
+
+	_, err = s.SetValue("key", "b", WriteOptions{Index: 6, CAS: casIndex(0)})
+	var casErr *CASError
+	require.ErrorAs(t, err, &casErr)
+	assert.Equal(t, uint64(5), casErr.ModifyIndex)
+
+	_, err = s.UpdateValue("key", "b", WriteOptions{Index: 7, CAS: casIndex(4)})
+	assert.ErrorIs(t, err, ErrCASConflict)
+
+	p, err = s.UpdateValue("key", "b", WriteOptions{Index: 8, CAS: casIndex(5)})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(8), p.ModifyIndex)
+
+	err = s.DeleteValue("key", WriteOptions{Index: 9, CAS: casIndex(5)})
+	assert.ErrorIs(t, err, ErrCASConflict)
+	require.NoError(t, s.DeleteValue("key", WriteOptions{Index: 10, CAS: casIndex(8)}))
+
+	_, err = s.GetValue("key")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+}
+
+func TestStore_LegacyValues(t *testing.T) {
+	s := newTestStore(t)
+
+	require.NoError(t, s.db.Update(func(tx *buntdb.Tx) error {
+		_, _, err := tx.Set("legacy", "plain", nil)
+		return err
+	}))
+
+	p, err := s.GetValue("legacy")
+	require.NoError(t, err)
+	assert.Equal(t, "plain", p.Value)
+	assert.Zero(t, p.ModifyIndex)
+}
+
+func TestStore_BinaryValues(t *testing.T) {
+	s := newTestStore(t)
+	blob := string([]byte{0x00, 0xff, 0xfe, 'a', 0x80})
+
+	_, err := s.SetValue("blob", blob, WriteOptions{Index: 1})
+	require.NoError(t, err)
+	_, err = s.SetValue("text", "héllo", WriteOptions{Index: 2})
+	require.NoError(t, err)
+
+	p, err := s.GetValue("blob")
+	require.NoError(t, err)
+	assert.Equal(t, blob, p.Value)
+	p, err = s.GetValue("text")
+	require.NoError(t, err)
+	assert.Equal(t, "héllo", p.Value)
+
+	// Text values keep the readable encoding.
+	require.NoError(t, s.db.View(func(tx *buntdb.Tx) error {
+		raw, err := tx.Get("text")
+		assert.Contains(t, raw, `"v":"héllo"`)
+		return err
+	}))
+}
+
+func TestStore_KeyMeta(t *testing.T) {
+	s := newTestStore(t)
+	meta := &KeyMeta{
+		Flags:       42,
+		ContentType: "application/json",
+		Labels:      map[string]string{"team": "infra"},
+	}
+
+	p, err := s.SetValue("config", `{"a":1}`, WriteOptions{Index: 1, Meta: meta})
+	require.NoError(t, err)
+	assert.Equal(t, meta, p.Meta)
+
+	// Writes without metadata keep it.
+	_, err = s.UpdateValue("config", `{"a":2}`, WriteOptions{Index: 2})
+	require.NoError(t, err)
+	p, err = s.GetValue("config")
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":2}`, p.Value)
+	assert.Equal(t, meta, p.Meta)
+
+	// Writes with metadata replace all of it.
+	_, err = s.SetValue("config", "a: 3", WriteOptions{
+		Index: 3,
+		Meta:  &KeyMeta{ContentType: "application/yaml"},
+	})
+	require.NoError(t, err)
+	p, err = s.GetValue("config")
+	require.NoError(t, err)
+	assert.Equal(t, &KeyMeta{ContentType: "application/yaml"}, p.Meta)
+
+	// Empty metadata clears it.
+	_, err = s.SetValue("config", "a: 4", WriteOptions{Index: 4, Meta: &KeyMeta{}})
+	require.NoError(t, err)
+	p, err = s.GetValue("config")
+	require.NoError(t, err)
+	assert.Nil(t, p.Meta)
+}
+
+func TestStore_ExpiredValues(t *testing.T) {
+	s := newTestStore(t)
+	now := time.Now()
+
+	_, err := s.SetValue("expired", "a", WriteOptions{
+		Index:     1,
+		ExpiresAt: now.Add(-time.Second).UnixNano(),
+	})
+	require.NoError(t, err)
+	_, err = s.SetValue("alive", "b", WriteOptions{
+		Index:     2,
+		ExpiresAt: now.Add(time.Minute).UnixNano(),
+	})
+	require.NoError(t, err)
+	_, err = s.SetValue("forever", "c", WriteOptions{Index: 3})
+	require.NoError(t, err)
+
+	pairs, err := s.ExpiredValues(now)
+	require.NoError(t, err)
+	require.Len(t, pairs, 1)
+	assert.Equal(t, "expired", pairs[0].Key)
+	assert.Zero(t, pairs[0].TTL)
+
+	p, err := s.GetValue("alive")
+	require.NoError(t, err)
+	assert.Equal(t, int64(60), p.TTL)
+}
+
+func TestStore_ExpiryRecords(t *testing.T) {
+	s := newTestStore(t)
+	now := time.Now()
+	past := now.Add(-time.Second).UnixNano()
+
+	_, err := s.SetValue("a", "1", WriteOptions{Index: 1, ExpiresAt: past})
+	require.NoError(t, err)
+	_, err = s.SetValue("b", "1", WriteOptions{Index: 2, ExpiresAt: past})
+	require.NoError(t, err)
+	_, err = s.SetValue("c", "1", WriteOptions{Index: 3, ExpiresAt: past})
+	require.NoError(t, err)
+
+	// Rewrites move the record of the key, deletes remove it.
+	_, err = s.UpdateValue("a", "2", WriteOptions{
+		Index:     4,
+		ExpiresAt: now.Add(time.Minute).UnixNano(),
+	})
+	require.NoError(t, err)
+	_, err = s.UpdateValue("b", "2", WriteOptions{Index: 5})
+	require.NoError(t, err)
+	require.NoError(t, s.DeleteValue("c", WriteOptions{Index: 6}))
+
+	pairs, err := s.ExpiredValues(now.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, pairs, 1)
+	assert.Equal(t, "a", pairs[0].Key)
+
+	require.NoError(t, s.db.View(func(tx *buntdb.Tx) error {
+		assert.Len(t, indexRecords(t, tx, ttlPrefix), 1)
+		return nil
+	}))
+}
+
+func TestStore_RebuildsIndex(t *testing.T) {
+	src := newTestStore(t)
+	_, err := src.SetValue("expired", "a", WriteOptions{
+		Index:     1,
+		ExpiresAt: time.Now().Add(-time.Second).UnixNano(),
+	})
+	require.NoError(t, err)
+	_, err = src.SessionCreate(&Session{ID: "s1", Behavior: SessionRelease}, 2)
+	require.NoError(t, err)
+	_, err = src.SetValue("locked", "a", WriteOptions{Index: 3, Acquire: "s1"})
+	require.NoError(t, err)
+	_, err = src.QueueEnqueue(&Task{ID: "claimed", Queue: "jobs", Priority: 1}, 4)
+	require.NoError(t, err)
+	_, err = src.QueueEnqueue(&Task{ID: "pending", Queue: "jobs"}, 5)
+	require.NoError(t, err)
+	_, err = src.QueueClaim("jobs", 0, 1, "c", 6)
+	require.NoError(t, err)
+
+	// Drop the records, as in a snapshot taken before they existed.
+	require.NoError(t, src.db.Update(func(tx *buntdb.Tx) error {
+		records := []string{indexVersionKey}
+		for _, prefix := range []string{
+			ttlPrefix, lockPrefix, queueWaitPrefix, queueReadyPrefix, queueLeasePrefix,
+		} {
+			records = append(records, indexRecords(t, tx, prefix)...)
+		}
+		for _, k := range records {
+			if _, err := tx.Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+	var buf bytes.Buffer
+	require.NoError(t, src.db.Save(&buf))
+
+	dst := newTestStore(t)
+	require.NoError(t, dst.Restore(io.NopCloser(&buf)))
+
+	pairs, err := dst.ExpiredValues(time.Now())
+	require.NoError(t, err)
+	require.Len(t, pairs, 1)
+	assert.Equal(t, "expired", pairs[0].Key)
+
+	released, _, err := dst.SessionDestroy("s1", nil, 7)
+	require.NoError(t, err)
+	require.Len(t, released, 1)
+	assert.Equal(t, "locked", released[0].Key)
+
+	expired, err := dst.ExpiredLeases(time.Now())
+	require.NoError(t, err)
+	require.Len(t, expired, 1)
+	assert.Equal(t, "claimed", expired[0].ID)
+	task, err := dst.QueueClaim("jobs", 0, 1, "c", 8)
+	require.NoError(t, err)
+	require.NotNil(t, task)
+	assert.Equal(t, "pending", task.ID)
+}
+
+func indexRecords(t *testing.T, tx *buntdb.Tx, prefix string) []string {
+	var records []string
+	require.NoError(t, tx.AscendRange("", prefix, prefixEnd(prefix), func(k, v string) bool {
+		records = append(records, k)
+		return true
+	}))
+
+	return records
+}
+
+func TestStore_Txn(t *testing.T) {
+	s := newTestStore(t)
+
+	_, err := s.SetValue("queued/1", "task", WriteOptions{Index: 1})
+	require.NoError(t, err)
+
+	results, err := s.Txn([]TxnOp{
+		{Type: TxnCheckIndex, Key: "queued/1", Index: 1},
+		{Type: TxnGet, Key: "queued/1"},
+		{Type: TxnDelete, Key: "queued/1"},
+		{Type: TxnSet, Key: "running/1", Value: "task"},
+	}, 2)
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+	assert.Equal(t, "task", results[1].Value)
+	assert.Equal(t, uint64(2), results[3].ModifyIndex)
+
+	_, err = s.Txn([]TxnOp{
+		{Type: TxnSet, Key: "queued/1", Value: "task"},
+		{Type: TxnCheckExists, Key: "missing"},
+	}, 3)
+	var txnErr *TxnError
+	require.ErrorAs(t, err, &txnErr)
+	assert.Equal(t, 1, txnErr.OpIndex)
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+
+	_, err = s.GetValue("queued/1")
+	assert.ErrorIs(t, err, ErrKeyNotFound, "failed txn must be rolled back")
+}
+
+func TestStore_ListValues(t *testing.T) {
+	s := newTestStore(t)
+
+	for i, k := range []string{
+		"tasks/a/1", "tasks/a/2", "tasks/b/1", "tasks/c", "tasksx", "other",
+	} {
+		_, err := s.SetValue(k, k, WriteOptions{Index: uint64(i + 1)})
+		require.NoError(t, err)
+	}
+
+	keys := func(pairs []Pair) []string {
+		var ks []string
+		for _, p := range pairs {
+			ks = append(ks, p.Key)
+		}
+		return ks
+	}
+
+	pairs, total, err := s.ListValues(ListOptions{Prefix: "tasks/"})
+	require.NoError(t, err)
+	assert.Equal(t, 4, total)
+	assert.Equal(t, []string{"tasks/a/1", "tasks/a/2", "tasks/b/1", "tasks/c"}, keys(pairs))
+	assert.Equal(t, "tasks/a/1", pairs[0].Value)
+
+	pairs, _, err = s.ListValues(ListOptions{Prefix: "tasks/", Separator: "/"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tasks/a/", "tasks/b/", "tasks/c"}, keys(pairs))
+	assert.Empty(t, pairs[0].Value)
+
+	pairs, _, err = s.ListValues(ListOptions{StartKey: "tasks/a/2", EndKey: "tasks/c"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tasks/a/2", "tasks/b/1"}, keys(pairs))
+
+	pairs, total, err = s.ListValues(ListOptions{Prefix: "tasks", Offset: 1, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 5, total)
+	assert.Equal(t, []string{"tasks/a/2", "tasks/b/1"}, keys(pairs))
+}
+
+func TestStore_FilePersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.db")
+
+	s, err := NewFileStore(path, SyncAlways, zap.NewNop().Sugar())
+	require.NoError(t, err)
+	_, err = s.SetValue("key", "value", WriteOptions{Index: 3})
+	require.NoError(t, err)
+	_, err = s.SetValue("key", "other", WriteOptions{Index: 4, CAS: casIndex(1)})
+	require.ErrorIs(t, err, ErrCASConflict)
+	require.NoError(t, s.Shutdown())
+
+	s, err = NewFileStore(path, SyncAlways, zap.NewNop().Sugar())
+	require.NoError(t, err)
+	defer s.Shutdown()
+
+	applied, err := s.AppliedIndex()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), applied, "failed writes do not move the applied index")
+
+	p, err := s.GetValue("key")
+	require.NoError(t, err)
+	assert.Equal(t, "value", p.Value)
+
+	pairs, err := s.GetAllValues()
+	require.NoError(t, err)
+	assert.Len(t, pairs, 1, "reserved keys are not listed")
+
+	_, err = s.GetValue(appliedIndexKey)
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+	_, err = s.SetValue(appliedIndexKey, "1", WriteOptions{Index: 5})
+	assert.ErrorIs(t, err, ErrReservedKey)
+
+	_, err = NewFileStore(path+".other", "sometimes", zap.NewNop().Sugar())
+	assert.Error(t, err)
+}
+
+func TestStore_Restore(t *testing.T) {
+	src := newTestStore(t)
+	_, err := src.SetValue("kept", "a", WriteOptions{Index: 7})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, src.db.Save(&buf))
+
+	dst := newTestStore(t)
+	_, err = dst.SetValue("stale", "b", WriteOptions{Index: 9})
+	require.NoError(t, err)
+
+	require.NoError(t, dst.Restore(io.NopCloser(&buf)))
+
+	_, err = dst.GetValue("stale")
+	assert.ErrorIs(t, err, ErrKeyNotFound, "restore replaces the existing data")
+	p, err := dst.GetValue("kept")
+	require.NoError(t, err)
+	assert.Equal(t, "a", p.Value)
+
+	applied, err := dst.AppliedIndex()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(7), applied)
+}
@@ -0,0 +1,238 @@
+package taskvault
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+var ErrTLSConfig = errors.New("ca-file is required for TLS and cert-file and key-file must be set together")
+
+// tlsConfigurator holds the certificate of this node and the CA that peers
+// are verified against. The configs it hands out look both up on every
+// handshake, so Reload takes effect for new connections without restarting
+// listeners or dropping pooled connections.
+type tlsConfigurator struct {
+	certFile   string
+	keyFile    string
+	caFile     string
+	serverName string
+
+	lock sync.RWMutex
+	cert *tls.Certificate
+	cas  *x509.CertPool
+}
+
+// newTLSConfigurator loads the certificates named in config. It returns nil
+// when TLS is not configured. Clients may omit the certificate, servers
+// need one.
+func newTLSConfigurator(config *Config) (*tlsConfigurator, error) {
+	if config.CertFile == "" && config.KeyFile == "" && config.CAFile == "" {
+		return nil, nil
+	}
+	if config.CAFile == "" || (config.CertFile == "") != (config.KeyFile == "") {
+		return nil, ErrTLSConfig
+	}
+
+	c := &tlsConfigurator{
+		certFile:   config.CertFile,
+		keyFile:    config.KeyFile,
+		caFile:     config.CAFile,
+		serverName: config.TLSServerName,
+	}
+	if err := c.Reload(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Reload reads the certificate, key and CA files again. The old ones stay in
+// use when any of them is invalid.
+func (c *tlsConfigurator) Reload() error {
+	cert := &tls.Certificate{}
+	if c.certFile != "" {
+		pair, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+		if err != nil {
+			return fmt.Errorf("tls: failed to load key pair: %w", err)
+		}
+		cert = &pair
+	}
+
+	pem, err := os.ReadFile(c.caFile)
+	if err != nil {
+		return fmt.Errorf("tls: failed to read CA file: %w", err)
+	}
+	cas := x509.NewCertPool()
+	if !cas.AppendCertsFromPEM(pem) {
+		return fmt.Errorf("tls: no certificates found in %s", c.caFile)
+	}
+
+	c.lock.Lock()
+	c.cert = cert
+	c.cas = cas
+	c.lock.Unlock()
+
+	return nil
+}
+
+func (c *tlsConfigurator) current() (*tls.Certificate, *x509.CertPool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.cert, c.cas
+}
+
+// IncomingConfig is the config of the RPC listener. Peers must present a
+// certificate signed by the CA.
+func (c *tlsConfigurator) IncomingConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, cas := c.current()
+			return &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*cert},
+				ClientAuth:   tls.RequireAndVerifyClientCert,
+				ClientCAs:    cas,
+				NextProtos:   []string{"h2"},
+			}, nil
+		},
+	}
+}
+
+// HTTPConfig is the config of the HTTPS listener. With verifyClients,
+// clients must present a certificate signed by the CA.
+func (c *tlsConfigurator) HTTPConfig(verifyClients bool) *tls.Config {
+	clientAuth := tls.NoClientCert
+	if verifyClients {
+		clientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			cert, _ := c.current()
+			return cert, nil
+		},
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, cas := c.current()
+			return &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*cert},
+				ClientAuth:   clientAuth,
+				ClientCAs:    cas,
+				NextProtos:   []string{"h2", "http/1.1"},
+			}, nil
+		},
+	}
+}
+
+// OutgoingConfig is the config used to dial the server at host. The
+// server's certificate must be signed by the CA and be valid for the
+// configured server name, or for host when none is configured.
+func (c *tlsConfigurator) OutgoingConfig(host string) *tls.Config {
+	name := c.serverName
+	if name == "" {
+		name = host
+	}
+
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, _ := c.current()
+			return cert, nil
+		},
+		// The chain is verified by VerifyConnection instead, against the CA
+		// that is current at handshake time.
+		InsecureSkipVerify: true,
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			return c.verifyServer(cs, name)
+		},
+	}
+}
+
+func (c *tlsConfigurator) verifyServer(cs tls.ConnectionState, name string) error {
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("tls: server presented no certificate")
+	}
+
+	_, cas := c.current()
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range cs.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err := cs.PeerCertificates[0].Verify(x509.VerifyOptions{
+		Roots:         cas,
+		Intermediates: intermediates,
+		DNSName:       name,
+	})
+
+	return err
+}
+
+// RPCDialOption returns the dial option for clients of the RPC port, nil
+// when TLS is not configured.
+func RPCDialOption(config *Config) (grpc.DialOption, error) {
+	c, err := newTLSConfigurator(config)
+	if err != nil || c == nil {
+		return nil, err
+	}
+
+	return grpc.WithTransportCredentials(c.TransportCredentials()), nil
+}
+
+// ClientTLSConfig returns the config for HTTPS clients of the server at
+// host, nil when TLS is not configured.
+func ClientTLSConfig(config *Config, host string) (*tls.Config, error) {
+	c, err := newTLSConfigurator(config)
+	if err != nil || c == nil {
+		return nil, err
+	}
+
+	return c.OutgoingConfig(host), nil
+}
+
+// TransportCredentials returns the gRPC credentials used to dial other
+// servers.
+func (c *tlsConfigurator) TransportCredentials() credentials.TransportCredentials {
+	return &tlsCredentials{
+		TransportCredentials: credentials.NewTLS(c.OutgoingConfig("")),
+		tls:                  c,
+	}
+}
+
+// tlsCredentials verifies each server against the host it was dialed by,
+// which credentials.NewTLS can not do with a reloadable CA.
+type tlsCredentials struct {
+	credentials.TransportCredentials
+	tls *tlsConfigurator
+}
+
+func (t *tlsCredentials) ClientHandshake(
+	ctx context.Context, authority string, conn net.Conn,
+) (net.Conn, credentials.AuthInfo, error) {
+	host, _, err := net.SplitHostPort(authority)
+	if err != nil {
+		host = authority
+	}
+
+	return credentials.NewTLS(t.tls.OutgoingConfig(host)).ClientHandshake(ctx, authority, conn)
+}
+
+func (t *tlsCredentials) Clone() credentials.TransportCredentials {
+	return &tlsCredentials{
+		TransportCredentials: t.TransportCredentials.Clone(),
+		tls:                  t.tls,
+	}
+}
@@ -0,0 +1,185 @@
+package taskvault
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "taskvault test CA"},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCA{
+		cert: cert,
+		key:  key,
+		pem:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+// issue writes a server and client certificate for 127.0.0.1 to dir.
+func (ca *testCA) issue(t *testing.T, dir string, serial int64) (certFile, keyFile string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "server"},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		DNSNames:     []string{"server.taskvault"},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+
+	return certFile, keyFile
+}
+
+func newTestTLS(t *testing.T, ca *testCA, serverName string) *tlsConfigurator {
+	dir := t.TempDir()
+	certFile, keyFile := ca.issue(t, dir, 2)
+	caFile := filepath.Join(dir, "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, ca.pem, 0o600))
+
+	c, err := newTLSConfigurator(&Config{
+		CertFile:      certFile,
+		KeyFile:       keyFile,
+		CAFile:        caFile,
+		TLSServerName: serverName,
+	})
+	require.NoError(t, err)
+
+	return c
+}
+
+// handshake runs a TLS handshake between server and client and returns the
+// client's error and the certificate the server presented.
+func handshake(server *tls.Config, client *tls.Config) (*x509.Certificate, error) {
+	sc, cc := net.Pipe()
+	defer sc.Close()
+	defer cc.Close()
+
+	go func() {
+		_ = tls.Server(sc, server).Handshake()
+		sc.Close()
+	}()
+
+	conn := tls.Client(cc, client)
+	if err := conn.Handshake(); err != nil {
+		return nil, err
+	}
+
+	return conn.ConnectionState().PeerCertificates[0], nil
+}
+
+func TestTLSConfigurator(t *testing.T) {
+	ca := newTestCA(t)
+	server := newTestTLS(t, ca, "")
+
+	_, err := handshake(server.IncomingConfig(), newTestTLS(t, ca, "").OutgoingConfig("127.0.0.1"))
+	assert.NoError(t, err)
+
+	_, err = handshake(server.IncomingConfig(), newTestTLS(t, ca, "").OutgoingConfig("127.0.0.2"))
+	assert.Error(t, err)
+
+	_, err = handshake(server.IncomingConfig(), newTestTLS(t, ca, "server.taskvault").OutgoingConfig("127.0.0.2"))
+	assert.NoError(t, err)
+
+	// Peers with certificates from another CA are rejected.
+	_, err = handshake(server.IncomingConfig(), newTestTLS(t, newTestCA(t), "").OutgoingConfig("127.0.0.1"))
+	assert.Error(t, err)
+}
+
+func TestTLSConfigurator_Reload(t *testing.T) {
+	ca := newTestCA(t)
+	server := newTestTLS(t, ca, "")
+	client := newTestTLS(t, ca, "")
+
+	cert, err := handshake(server.IncomingConfig(), client.OutgoingConfig("127.0.0.1"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), cert.SerialNumber.Int64())
+
+	ca.issue(t, filepath.Dir(server.certFile), 3)
+	require.NoError(t, server.Reload())
+
+	cert, err = handshake(server.IncomingConfig(), client.OutgoingConfig("127.0.0.1"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), cert.SerialNumber.Int64())
+
+	// Invalid files leave the loaded certificate in place.
+	require.NoError(t, os.WriteFile(server.certFile, []byte("garbage"), 0o600))
+	assert.Error(t, server.Reload())
+	cert, err = handshake(server.IncomingConfig(), client.OutgoingConfig("127.0.0.1"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), cert.SerialNumber.Int64())
+}
+
+func TestNewTLSConfigurator_Partial(t *testing.T) {
+	c, err := newTLSConfigurator(&Config{})
+	assert.NoError(t, err)
+	assert.Nil(t, c)
+
+	_, err = newTLSConfigurator(&Config{CertFile: "cert.pem", KeyFile: "key.pem"})
+	assert.ErrorIs(t, err, ErrTLSConfig)
+
+	_, err = newTLSConfigurator(&Config{CAFile: "ca.pem", CertFile: "cert.pem"})
+	assert.ErrorIs(t, err, ErrTLSConfig)
+}
+
+func TestTLSConfigurator_HTTP(t *testing.T) {
+	ca := newTestCA(t)
+	server := newTestTLS(t, ca, "")
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, ca.pem, 0o600))
+	anonymous, err := newTLSConfigurator(&Config{CAFile: caFile})
+	require.NoError(t, err)
+
+	_, err = handshake(server.HTTPConfig(false), anonymous.OutgoingConfig("127.0.0.1"))
+	assert.NoError(t, err)
+
+	_, err = handshake(server.HTTPConfig(true), newTestTLS(t, ca, "").OutgoingConfig("127.0.0.1"))
+	assert.NoError(t, err)
+}
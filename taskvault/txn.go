@@ -0,0 +1,101 @@
+package taskvault
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/danluki/taskvault/pkg/types"
+)
+
+// maxTxnOps bounds the size of a transaction so a single log entry stays small.
+const maxTxnOps = 64
+
+type TxnOpType int
+
+const (
+	TxnGet TxnOpType = iota
+	TxnSet
+	TxnDelete
+	TxnCheckIndex
+	TxnCheckExists
+)
+
+var txnOpNames = map[string]TxnOpType{
+	"get":          TxnGet,
+	"set":          TxnSet,
+	"delete":       TxnDelete,
+	"check-index":  TxnCheckIndex,
+	"check-exists": TxnCheckExists,
+}
+
+// TxnOp is a single operation of a transaction applied by Store.Txn.
+type TxnOp struct {
+	Type      TxnOpType
+	Key       string
+	Value     string
+	Index     uint64
+	ExpiresAt int64
+}
+
+// TxnError reports the operation that caused a transaction to be rolled back.
+type TxnError struct {
+	OpIndex int
+	Err     error
+}
+
+func (e *TxnError) Error() string {
+	return fmt.Sprintf("txn op %d failed: %s", e.OpIndex, e.Err)
+}
+
+func (e *TxnError) Unwrap() error {
+	return e.Err
+}
+
+func parseTxnOpType(verb string) (types.TxnOp_Type, error) {
+	t, ok := txnOpNames[strings.ToLower(verb)]
+	if !ok {
+		return 0, fmt.Errorf("unknown txn verb %q", verb)
+	}
+
+	return types.TxnOp_Type(t), nil
+}
+
+func txnOpsFromProto(ops []*types.TxnOp) []TxnOp {
+	res := make([]TxnOp, len(ops))
+	for i, op := range ops {
+		res[i] = TxnOp{
+			Type:      TxnOpType(op.Type),
+			Key:       op.Key,
+			Value:     string(op.Value),
+			Index:     op.Index,
+			ExpiresAt: op.ExpiresAt,
+		}
+	}
+
+	return res
+}
+
+func validateTxn(req *types.TxnRequest) error {
+	if len(req.Ops) == 0 {
+		return fmt.Errorf("txn has no operations")
+	}
+	if len(req.Ops) > maxTxnOps {
+		return fmt.Errorf(
+			"txn has %d operations, at most %d are allowed", len(req.Ops), maxTxnOps,
+		)
+	}
+
+	for i, op := range req.Ops {
+		if op.Key == "" {
+			return &TxnError{OpIndex: i, Err: fmt.Errorf("missing key")}
+		}
+		if isReservedKey(op.Key) {
+			return &TxnError{OpIndex: i, Err: ErrReservedKey}
+		}
+		if _, ok := types.TxnOp_Type_name[int32(op.Type)]; !ok {
+			return &TxnError{OpIndex: i, Err: fmt.Errorf("unknown op type %d", op.Type)}
+		}
+	}
+
+	return nil
+}
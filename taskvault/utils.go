@@ -10,10 +10,12 @@ import (
 )
 
 type ServerParts struct {
-	Name         string
-	ID           string
-	Port         int
-	Bootstrap    bool
+	Name      string
+	ID        string
+	Port      int
+	Bootstrap bool
+	// NonVoter is set for replicas, which join Raft without a vote.
+	NonVoter     bool
 	Expect       int
 	RaftVersion  int
 	BuildVersion *version.Version
@@ -74,6 +76,7 @@ func toServerPart(m serf.Member) *ServerParts {
 		ID:           m.Name,
 		Port:         port,
 		Bootstrap:    bootstrap,
+		NonVoter:     m.Tags[roleTag] == roleReplica,
 		Expect:       expect,
 		Addr:         &net.TCPAddr{IP: m.Addr, Port: port},
 		RPCAddr:      &net.TCPAddr{IP: rpcIP, Port: port},
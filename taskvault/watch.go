@@ -0,0 +1,262 @@
+package taskvault
+
+import (
+	"errors"
+	"strings"
+	"sync"
+)
+
+const (
+	// watchHistorySize is the number of recent events kept for watches that
+	// resume from an index.
+	watchHistorySize = 4096
+	// watchSubscriptionBuffer is how many events a stream may lag behind
+	// before it is dropped.
+	watchSubscriptionBuffer = 256
+)
+
+var ErrCompacted = errors.New("requested index has been compacted")
+
+type WatchEventType int
+
+const (
+	WatchPut WatchEventType = iota
+	WatchDelete
+)
+
+// WatchEvent is a change applied by the FSM. For deletes Pair only carries
+// the key.
+type WatchEvent struct {
+	Type  WatchEventType
+	Index uint64
+	Pair  Pair
+}
+
+// watcher is notified when a key, or any key under a prefix, changes.
+type watcher struct {
+	key    string
+	prefix bool
+
+	// ch holds the highest index of a matching change not yet consumed. An
+	// index of 0 means the whole store was replaced by a snapshot restore.
+	ch chan uint64
+}
+
+func (w *watcher) matches(key string) bool {
+	return matchesKey(w.key, w.prefix, key)
+}
+
+func matchesKey(watched string, prefix bool, key string) bool {
+	if prefix {
+		return strings.HasPrefix(key, watched)
+	}
+
+	return watched == key
+}
+
+// subscription receives every matching event in order. ch is closed when the
+// subscriber falls too far behind or the store is replaced by a snapshot.
+type subscription struct {
+	key    string
+	prefix bool
+
+	ch chan WatchEvent
+}
+
+// watchHub fans out the changes applied by the FSM to blocking readers and
+// watch streams, and keeps a short history so streams can resume.
+type watchHub struct {
+	lock          sync.Mutex
+	watchers      map[*watcher]struct{}
+	subscriptions map[*subscription]struct{}
+
+	history []WatchEvent
+	// compacted is the index up to which events are no longer available.
+	compacted uint64
+	// restored is set after a snapshot restore until the next event, as
+	// the index the snapshot was taken at is not known to the hub.
+	restored bool
+}
+
+func newWatchHub() *watchHub {
+	return &watchHub{
+		watchers:      make(map[*watcher]struct{}),
+		subscriptions: make(map[*subscription]struct{}),
+	}
+}
+
+// Watch registers interest in key, or in every key starting with key when
+// prefix is set. Callers must Stop the watcher when done.
+func (h *watchHub) Watch(key string, prefix bool) *watcher {
+	w := &watcher{
+		key:    key,
+		prefix: prefix,
+		ch:     make(chan uint64, 1),
+	}
+
+	h.lock.Lock()
+	h.watchers[w] = struct{}{}
+	h.lock.Unlock()
+
+	return w
+}
+
+func (h *watchHub) Stop(w *watcher) {
+	h.lock.Lock()
+	delete(h.watchers, w)
+	h.lock.Unlock()
+}
+
+// Subscribe starts a stream of events for key. When startIndex is set the
+// retained events after it are returned as backlog, registered atomically
+// with the subscription so none are lost or duplicated. appliedIndex is
+// the last index applied to the store, used as the compaction point right
+// after a snapshot restore.
+func (h *watchHub) Subscribe(
+	key string, prefix bool, startIndex uint64, appliedIndex uint64,
+) (*subscription, []WatchEvent, error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.restored {
+		h.compacted = max(h.compacted, appliedIndex)
+	}
+
+	var backlog []WatchEvent
+	if startIndex > 0 {
+		if startIndex < h.compacted {
+			return nil, nil, ErrCompacted
+		}
+		for _, ev := range h.history {
+			if ev.Index > startIndex && matchesKey(key, prefix, ev.Pair.Key) {
+				backlog = append(backlog, ev)
+			}
+		}
+	}
+
+	sub := &subscription{
+		key:    key,
+		prefix: prefix,
+		ch:     make(chan WatchEvent, watchSubscriptionBuffer),
+	}
+	h.subscriptions[sub] = struct{}{}
+
+	return sub, backlog, nil
+}
+
+func (h *watchHub) Unsubscribe(sub *subscription) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if _, ok := h.subscriptions[sub]; ok {
+		delete(h.subscriptions, sub)
+		close(sub.ch)
+	}
+}
+
+// publish records the events applied at index and notifies the watchers.
+func (h *watchHub) publish(index uint64, events ...WatchEvent) {
+	if len(events) == 0 {
+		return
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.restored {
+		h.compacted = index - 1
+		h.restored = false
+	}
+
+	for _, ev := range events {
+		h.history = append(h.history, ev)
+	}
+	if over := len(h.history) - watchHistorySize; over > 0 {
+		h.compacted = h.history[over-1].Index
+		h.history = append(h.history[:0:0], h.history[over:]...)
+	}
+
+	for w := range h.watchers {
+		for _, ev := range events {
+			if w.matches(ev.Pair.Key) {
+				w.notify(index)
+				break
+			}
+		}
+	}
+
+	for sub := range h.subscriptions {
+		for _, ev := range events {
+			if !matchesKey(sub.key, sub.prefix, ev.Pair.Key) {
+				continue
+			}
+			select {
+			case sub.ch <- ev:
+			default:
+				delete(h.subscriptions, sub)
+				close(sub.ch)
+			}
+			if _, ok := h.subscriptions[sub]; !ok {
+				break
+			}
+		}
+	}
+}
+
+// lastIndex returns the index of the last retained change to key, or to any
+// key under it when prefix is set. ok is false when none is retained, the
+// change may then have been compacted and callers fall back to the applied
+// index.
+func (h *watchHub) lastIndex(key string, prefix bool) (index uint64, ok bool) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	for i := len(h.history) - 1; i >= 0; i-- {
+		if matchesKey(key, prefix, h.history[i].Pair.Key) {
+			return h.history[i].Index, true
+		}
+	}
+
+	return 0, false
+}
+
+// compact drops the events up to index, a Subscribe resuming from before
+// it fails with ErrCompacted.
+func (h *watchHub) compact(index uint64) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.compacted = max(h.compacted, index)
+}
+
+// reset wakes every watcher and ends every stream, used when a snapshot
+// replaces the store.
+func (h *watchHub) reset() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	for w := range h.watchers {
+		w.notify(0)
+	}
+	for sub := range h.subscriptions {
+		delete(h.subscriptions, sub)
+		close(sub.ch)
+	}
+
+	h.history = nil
+	h.restored = true
+}
+
+// notify must be called with the hub lock held, which makes the hub the only
+// sender and lets a pending notification be merged with the new one.
+func (w *watcher) notify(index uint64) {
+	select {
+	case pending := <-w.ch:
+		if pending == 0 || (index != 0 && pending > index) {
+			index = pending
+		}
+	default:
+	}
+
+	w.ch <- index
+}
@@ -0,0 +1,78 @@
+package taskvault
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func putEvent(index uint64, key string) WatchEvent {
+	return WatchEvent{
+		Type:  WatchPut,
+		Index: index,
+		Pair:  Pair{Key: key, ModifyIndex: index},
+	}
+}
+
+func TestWatchHub_SubscribeResume(t *testing.T) {
+	h := newWatchHub()
+	h.publish(3, putEvent(3, "tasks/1"))
+	h.publish(4, putEvent(4, "other"))
+	h.publish(5, putEvent(5, "tasks/2"))
+
+	sub, backlog, err := h.Subscribe("tasks/", true, 3, 5)
+	require.NoError(t, err)
+	defer h.Unsubscribe(sub)
+	require.Len(t, backlog, 1)
+	assert.Equal(t, "tasks/2", backlog[0].Pair.Key)
+
+	h.publish(6, putEvent(6, "tasks/1"))
+	ev := <-sub.ch
+	assert.Equal(t, uint64(6), ev.Index)
+}
+
+func TestWatchHub_Compacted(t *testing.T) {
+	h := newWatchHub()
+	for i := uint64(1); i <= watchHistorySize+10; i++ {
+		h.publish(i, putEvent(i, "k"))
+	}
+
+	_, _, err := h.Subscribe("k", false, 5, 0)
+	assert.ErrorIs(t, err, ErrCompacted)
+
+	sub, backlog, err := h.Subscribe("k", false, watchHistorySize, 0)
+	require.NoError(t, err)
+	h.Unsubscribe(sub)
+	assert.Len(t, backlog, 10)
+
+	h.reset()
+	_, _, err = h.Subscribe("k", false, 20, 100)
+	assert.ErrorIs(t, err, ErrCompacted, "history before a restore is gone")
+
+	sub, _, err = h.Subscribe("k", false, 100, 100)
+	require.NoError(t, err)
+	h.Unsubscribe(sub)
+}
+
+func TestWatchHub_LastIndex(t *testing.T) {
+	h := newWatchHub()
+	h.publish(3, putEvent(3, "tasks/1"))
+	h.publish(4, WatchEvent{Type: WatchDelete, Index: 4, Pair: Pair{Key: "tasks/2"}})
+	h.publish(5, putEvent(5, "other"))
+
+	index, ok := h.lastIndex("tasks/", true)
+	require.True(t, ok)
+	assert.Equal(t, uint64(4), index, "deletes raise the index")
+
+	index, ok = h.lastIndex("tasks/1", false)
+	require.True(t, ok)
+	assert.Equal(t, uint64(3), index)
+
+	_, ok = h.lastIndex("jobs/", true)
+	assert.False(t, ok)
+
+	h.reset()
+	_, ok = h.lastIndex("tasks/", true)
+	assert.False(t, ok, "history before a restore is gone")
+}